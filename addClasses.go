@@ -2,8 +2,6 @@ package wit
 
 import (
 	"strconv"
-
-	"golang.org/x/net/html"
 )
 
 // AddClasses adds provided classes to matching elements
@@ -13,43 +11,25 @@ type AddClasses struct {
 
 // Apply applies the delta to the provided elements
 func (a AddClasses) Apply(d Document) {
-	class := a.Classes
-	classesToAdd := parseClass(class)
-
-	for _, node := range d.nodes {
-		if node.Type != html.ElementNode {
-			continue
-		}
-
-		found := false
-		for i, att := range node.Attr {
-			if att.Namespace != "" {
-				continue
-			}
-
-			if att.Key == "class" {
-				parsed := parseClass(att.Val)
-				for key, value := range classesToAdd {
-					parsed[key] = value
-				}
-
-				att.Val = buildClass(parsed)
-				node.Attr[i] = att
-				found = true
-				break
-			}
-		}
-
-		if !found {
-			node.Attr = append(node.Attr, html.Attribute{
-				Key: "class",
-				Val: buildClass(classesToAdd),
-			})
-		}
-	}
+	AddTokens{"class", a.Classes}.Apply(d)
 }
 
 // MarshalJSON marshals the delta to JSON format
 func (a AddClasses) MarshalJSON() ([]byte, error) {
 	return []byte("[" + addClassesLabelJSON + "," + strconv.Quote(a.Classes) + "]"), nil
 }
+
+// MarshalBinary marshals the delta to the binary format
+func (a AddClasses) MarshalBinary() ([]byte, error) {
+	return marshalBinary(a)
+}
+
+// UnmarshalBinary sets *a to the delta encoded in the binary format
+func (a *AddClasses) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, a)
+}
+
+// Op returns the operation performed by the delta
+func (a AddClasses) Op() Op {
+	return OpAddClasses
+}
@@ -0,0 +1,78 @@
+package wit
+
+import (
+	"strconv"
+
+	"golang.org/x/net/html"
+)
+
+// AddTokens adds provided tokens to the space-separated attribute of
+// matching elements, such as rel or aria-describedby
+type AddTokens struct {
+	Attr   string
+	Tokens string
+}
+
+// Apply applies the delta to the provided elements
+func (a AddTokens) Apply(d Document) {
+	tokensToAdd := parseTokens(a.Tokens)
+
+	for _, node := range d.nodes {
+		if node.Type != html.ElementNode {
+			continue
+		}
+
+		found := false
+		for i, att := range node.Attr {
+			if att.Namespace != "" {
+				continue
+			}
+
+			if att.Key == a.Attr {
+				parsed := parseTokens(att.Val)
+				present := map[string]bool{}
+				for _, token := range parsed {
+					present[token] = true
+				}
+
+				for _, token := range tokensToAdd {
+					if !present[token] {
+						parsed = append(parsed, token)
+					}
+				}
+
+				att.Val = buildTokens(parsed)
+				node.Attr[i] = att
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			node.Attr = append(node.Attr, html.Attribute{
+				Key: a.Attr,
+				Val: buildTokens(tokensToAdd),
+			})
+		}
+	}
+}
+
+// MarshalJSON marshals the delta to JSON format
+func (a AddTokens) MarshalJSON() ([]byte, error) {
+	return []byte("[" + addTokensLabelJSON + "," + strconv.Quote(a.Attr) + "," + strconv.Quote(a.Tokens) + "]"), nil
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (a AddTokens) MarshalBinary() ([]byte, error) {
+	return marshalBinary(a)
+}
+
+// UnmarshalBinary sets *a to the delta encoded in the binary format
+func (a *AddTokens) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, a)
+}
+
+// Op returns the operation performed by the delta
+func (a AddTokens) Op() Op {
+	return OpAddTokens
+}
@@ -1,7 +1,7 @@
 package wit
 
 import (
-	"strconv"
+	"bytes"
 
 	"github.com/andybalholm/cascadia"
 	"golang.org/x/net/html"
@@ -27,5 +27,32 @@ func (a All) Apply(d Document) {
 
 // MarshalJSON marshals the delta to JSON format
 func (a All) MarshalJSON() ([]byte, error) {
-	return []byte("[" + selectorAllLabelJSON + "," + strconv.Quote(a.Selector.String()) + deltaToCSV(a.Delta) + "]"), nil
+	return marshalJSON(a)
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (a All) MarshalBinary() ([]byte, error) {
+	return marshalBinary(a)
+}
+
+// UnmarshalBinary sets *a to the delta encoded in the binary format
+func (a *All) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, a)
+}
+
+// Op returns the operation performed by the delta
+func (a All) Op() Op {
+	return OpAll
+}
+
+func (a All) writeJSON(b *bytes.Buffer) error {
+	writeJSONOpen(b, selectorAllLabelJSON)
+	writeJSONString(b, a.Selector.String())
+
+	if err := writeJSONDeltaParameter(b, a.Delta); err != nil {
+		return err
+	}
+
+	b.WriteByte(']')
+	return nil
 }
@@ -14,6 +14,11 @@ type Append struct {
 // Apply applies the delta to the provided elements
 func (a Append) Apply(d Document) {
 	for _, node := range d.nodes {
+		if isRangeStart(node) {
+			insertInRange(node, a.HTMLSource.Nodes(node.Parent), true)
+			continue
+		}
+
 		if node.Type != html.ElementNode {
 			continue
 		}
@@ -29,3 +34,18 @@ func (a Append) Apply(d Document) {
 func (a Append) MarshalJSON() ([]byte, error) {
 	return []byte("[" + appendLabelJSON + "," + strconv.Quote(a.HTMLSource.String()) + "]"), nil
 }
+
+// MarshalBinary marshals the delta to the binary format
+func (a Append) MarshalBinary() ([]byte, error) {
+	return marshalBinary(a)
+}
+
+// UnmarshalBinary sets *a to the delta encoded in the binary format
+func (a *Append) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, a)
+}
+
+// Op returns the operation performed by the delta
+func (a Append) Op() Op {
+	return OpAppend
+}
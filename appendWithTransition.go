@@ -0,0 +1,40 @@
+package wit
+
+import (
+	"strconv"
+	"time"
+)
+
+// AppendWithTransition appends the provided HTML to matching elements with
+// the provided enter classes, which are removed once their transition ends
+// or the timeout expires. The server-side document never holds the classes.
+type AppendWithTransition struct {
+	HTMLSource
+	Classes string
+	Timeout time.Duration
+}
+
+// Apply applies the delta to the provided elements
+func (a AppendWithTransition) Apply(d Document) {
+	Append{a.HTMLSource}.Apply(d)
+}
+
+// MarshalJSON marshals the delta to JSON format
+func (a AppendWithTransition) MarshalJSON() ([]byte, error) {
+	return []byte("[" + appendWithTransitionLabelJSON + "," + strconv.Quote(a.HTMLSource.String()) + "," + strconv.Quote(a.Classes) + "," + strconv.FormatInt(a.Timeout.Milliseconds(), 10) + "]"), nil
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (a AppendWithTransition) MarshalBinary() ([]byte, error) {
+	return marshalBinary(a)
+}
+
+// UnmarshalBinary sets *a to the delta encoded in the binary format
+func (a *AppendWithTransition) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, a)
+}
+
+// Op returns the operation performed by the delta
+func (a AppendWithTransition) Op() Op {
+	return OpAppendWithTransition
+}
@@ -39,6 +39,35 @@ var expectedJSON = `[1,[3,"body",[12,"<div class=\"one\"></div>"],[14,"<div clas
 var baseDocument = NewDocument()
 var expectedHTML = `<!DOCTYPE html><html><head></head><body class="bar"><h1 bar="foo" bar3="foo3"></h1><hr/><div number="one"></div><hr/><div class="two"></div><hr/><div class="three" style="border: 1px solid;" foo="bar"></div></body></html>`
 
+func render(doc Document) string {
+	var b bytes.Buffer
+	doc.Render(&b)
+	return b.String()
+}
+
+// assertJSONRoundTrip checks that the delta survives being marshalled and
+// unmarshalled, and returns its JSON form
+func assertJSONRoundTrip(t *testing.T, delta Delta) string {
+	t.Helper()
+
+	payload, err := delta.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result List
+	if err := result.UnmarshalJSON(payload); err != nil {
+		t.Fatal(err)
+	}
+
+	resultJSON, _ := result.MarshalJSON()
+	if string(resultJSON) != string(payload) {
+		t.Error("Expected ", string(payload), ", got", string(resultJSON))
+	}
+
+	return string(payload)
+}
+
 func TestJSONMarshal(t *testing.T) {
 	result, _ := delta.MarshalJSON()
 
@@ -0,0 +1,672 @@
+package wit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// The binary format mirrors the JSON one value by value, replacing quoting
+// and escaping by tags, varints and length-prefixed strings. Deltas, i.e.
+// arrays starting with a non-negative integer, get their own tag so that
+// their label takes a single varint.
+const (
+	binaryNull byte = iota
+	binaryFalse
+	binaryTrue
+	binaryInt
+	binaryFloat
+	binaryString
+	binaryArray
+	binaryObject
+	binaryDelta
+)
+
+const maxBinaryDepth = 1000
+
+var errBinaryTooDeep = errors.New("wit: binary value nested too deeply")
+
+func marshalBinary(delta Delta) ([]byte, error) {
+	b := getBuffer()
+	defer putBuffer(b)
+
+	if err := writeBinaryDelta(b, delta); err != nil {
+		return nil, err
+	}
+
+	return append([]byte(nil), b.Bytes()...), nil
+}
+
+// transcodeBinary writes the binary encoding of the provided JSON, which is
+// how custom deltas are encoded
+func transcodeBinary(b *bytes.Buffer, payload []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+
+	return writeBinaryValue(b, value)
+}
+
+func writeBinaryOpen(b *bytes.Buffer, op Op, count int) {
+	b.WriteByte(binaryDelta)
+	writeUvarint(b, uint64(op))
+	writeUvarint(b, uint64(count))
+}
+
+func writeBinaryStringValue(b *bytes.Buffer, str string) {
+	b.WriteByte(binaryString)
+	writeBinaryString(b, str)
+}
+
+func writeBinaryInt(b *bytes.Buffer, i int64) {
+	b.WriteByte(binaryInt)
+	writeVarint(b, i)
+}
+
+func writeBinaryBool(b *bytes.Buffer, v bool) {
+	if v {
+		b.WriteByte(binaryTrue)
+	} else {
+		b.WriteByte(binaryFalse)
+	}
+}
+
+func writeBinaryStrMap(b *bytes.Buffer, m map[string]string) {
+	b.WriteByte(binaryObject)
+	writeUvarint(b, uint64(len(m)))
+	for _, key := range sortedKeys(m) {
+		writeBinaryString(b, key)
+		writeBinaryStringValue(b, m[key])
+	}
+}
+
+func writeBinaryDeltas(b *bytes.Buffer, deltas []Delta) error {
+	for _, delta := range deltas {
+		if err := writeBinaryDelta(b, delta); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// binaryParameterCount returns the number of trailing parameters the
+// provided child delta takes in its parent, lists being flattened
+func binaryParameterCount(delta Delta) int {
+	if list, ok := delta.(List); ok {
+		return len(list.Deltas)
+	}
+
+	return 1
+}
+
+func writeBinaryDeltaParameter(b *bytes.Buffer, delta Delta) error {
+	if list, ok := delta.(List); ok {
+		return writeBinaryDeltas(b, list.Deltas)
+	}
+
+	return writeBinaryDelta(b, delta)
+}
+
+// writeBinaryDelta writes the binary encoding of standard deltas directly,
+// following the layout of their JSON encoding
+func writeBinaryDelta(b *bytes.Buffer, delta Delta) error {
+	switch d := delta.(type) {
+	case List:
+		writeBinaryOpen(b, OpList, len(d.Deltas))
+		return writeBinaryDeltas(b, d.Deltas)
+	case Root:
+		writeBinaryOpen(b, OpRoot, binaryParameterCount(d.Delta))
+		return writeBinaryDeltaParameter(b, d.Delta)
+	case First:
+		writeBinaryOpen(b, OpFirst, 1+binaryParameterCount(d.Delta))
+		writeBinaryStringValue(b, d.Selector.String())
+		return writeBinaryDeltaParameter(b, d.Delta)
+	case All:
+		writeBinaryOpen(b, OpAll, 1+binaryParameterCount(d.Delta))
+		writeBinaryStringValue(b, d.Selector.String())
+		return writeBinaryDeltaParameter(b, d.Delta)
+	case Parent:
+		writeBinaryOpen(b, OpParent, binaryParameterCount(d.Delta))
+		return writeBinaryDeltaParameter(b, d.Delta)
+	case FirstChild:
+		writeBinaryOpen(b, OpFirstChild, binaryParameterCount(d.Delta))
+		return writeBinaryDeltaParameter(b, d.Delta)
+	case LastChild:
+		writeBinaryOpen(b, OpLastChild, binaryParameterCount(d.Delta))
+		return writeBinaryDeltaParameter(b, d.Delta)
+	case PrevSibling:
+		writeBinaryOpen(b, OpPrevSibling, binaryParameterCount(d.Delta))
+		return writeBinaryDeltaParameter(b, d.Delta)
+	case NextSibling:
+		writeBinaryOpen(b, OpNextSibling, binaryParameterCount(d.Delta))
+		return writeBinaryDeltaParameter(b, d.Delta)
+	case Delay:
+		writeBinaryOpen(b, OpDelay, 1+binaryParameterCount(d.Delta))
+		writeBinaryInt(b, d.Duration.Milliseconds())
+		return writeBinaryDeltaParameter(b, d.Delta)
+	case Range:
+		writeBinaryOpen(b, OpRange, 1+binaryParameterCount(d.Delta))
+		writeBinaryStringValue(b, d.Name)
+		return writeBinaryDeltaParameter(b, d.Delta)
+
+	case Remove, Clear, Focus, Blur, Reload:
+		writeBinaryOpen(b, OpOf(d), 0)
+
+	case HTML:
+		writeBinaryOpen(b, OpHTML, 1)
+		writeBinaryStringValue(b, d.HTMLSource.String())
+	case Replace:
+		writeBinaryOpen(b, OpReplace, 1)
+		writeBinaryStringValue(b, d.HTMLSource.String())
+	case Append:
+		writeBinaryOpen(b, OpAppend, 1)
+		writeBinaryStringValue(b, d.HTMLSource.String())
+	case Prepend:
+		writeBinaryOpen(b, OpPrepend, 1)
+		writeBinaryStringValue(b, d.HTMLSource.String())
+	case InsertAfter:
+		writeBinaryOpen(b, OpInsertAfter, 1)
+		writeBinaryStringValue(b, d.HTMLSource.String())
+	case InsertBefore:
+		writeBinaryOpen(b, OpInsertBefore, 1)
+		writeBinaryStringValue(b, d.HTMLSource.String())
+	case MergeHead:
+		writeBinaryOpen(b, OpMergeHead, 1)
+		writeBinaryStringValue(b, d.HTMLSource.String())
+
+	case SetAttr:
+		writeBinaryOpen(b, OpSetAttr, 1)
+		writeBinaryStrMap(b, d.Attributes)
+	case ReplaceAttr:
+		writeBinaryOpen(b, OpReplaceAttr, 1)
+		writeBinaryStrMap(b, d.Attributes)
+	case RmAttr:
+		writeBinaryOpen(b, OpRmAttr, len(d.Attributes))
+		for _, attr := range d.Attributes {
+			writeBinaryStringValue(b, attr)
+		}
+	case SetStyles:
+		writeBinaryOpen(b, OpSetStyles, 1)
+		writeBinaryStrMap(b, d.Styles)
+	case RmStyles:
+		writeBinaryOpen(b, OpRmStyles, len(d.Styles))
+		for _, style := range d.Styles {
+			writeBinaryStringValue(b, style)
+		}
+	case AddClasses:
+		writeBinaryOpen(b, OpAddClasses, 1)
+		writeBinaryStringValue(b, d.Classes)
+	case RmClasses:
+		writeBinaryOpen(b, OpRmClasses, 1)
+		writeBinaryStringValue(b, d.Classes)
+	case AddTokens:
+		writeBinaryOpen(b, OpAddTokens, 2)
+		writeBinaryStringValue(b, d.Attr)
+		writeBinaryStringValue(b, d.Tokens)
+	case RmTokens:
+		writeBinaryOpen(b, OpRmTokens, 2)
+		writeBinaryStringValue(b, d.Attr)
+		writeBinaryStringValue(b, d.Tokens)
+
+	case LoadScript:
+		writeBinaryOpen(b, OpLoadScript, 2)
+		writeBinaryStringValue(b, d.Src)
+		writeBinaryStrMap(b, d.Attributes)
+	case LoadStylesheet:
+		writeBinaryOpen(b, OpLoadStylesheet, 2)
+		writeBinaryStringValue(b, d.Href)
+		writeBinaryStrMap(b, d.Attributes)
+	case ReconcileChildren:
+		writeBinaryOpen(b, OpReconcileChildren, 2*len(d.Children))
+		for _, keyed := range d.Children {
+			writeBinaryStringValue(b, keyed.Key)
+			if keyed.HTMLSource == nil {
+				b.WriteByte(binaryNull)
+			} else {
+				writeBinaryStringValue(b, keyed.HTMLSource.String())
+			}
+		}
+
+	case ScrollIntoView:
+		writeBinaryOpen(b, OpScrollIntoView, 1)
+		writeBinaryStrMap(b, d.Options)
+	case ScrollTo:
+		writeBinaryOpen(b, OpScrollTo, 2)
+		writeBinaryInt(b, int64(d.Top))
+		writeBinaryInt(b, int64(d.Left))
+	case SelectText:
+		writeBinaryOpen(b, OpSelectText, 2)
+		writeBinaryInt(b, int64(d.Start))
+		writeBinaryInt(b, int64(d.End))
+	case DispatchEvent:
+		writeBinaryOpen(b, OpDispatchEvent, 3)
+		writeBinaryStringValue(b, d.Type)
+		if len(d.Detail) == 0 {
+			b.WriteByte(binaryNull)
+		} else if err := transcodeBinary(b, d.Detail); err != nil {
+			return err
+		}
+
+		writeBinaryBool(b, d.Bubbles)
+
+	case RemoveWithTransition:
+		writeBinaryOpen(b, OpRemoveWithTransition, 2)
+		writeBinaryStringValue(b, d.Classes)
+		writeBinaryInt(b, d.Timeout.Milliseconds())
+	case AppendWithTransition:
+		writeBinaryOpen(b, OpAppendWithTransition, 3)
+		writeBinaryStringValue(b, d.HTMLSource.String())
+		writeBinaryStringValue(b, d.Classes)
+		writeBinaryInt(b, d.Timeout.Milliseconds())
+	case InsertAfterWithTransition:
+		writeBinaryOpen(b, OpInsertAfterWithTransition, 3)
+		writeBinaryStringValue(b, d.HTMLSource.String())
+		writeBinaryStringValue(b, d.Classes)
+		writeBinaryInt(b, d.Timeout.Milliseconds())
+
+	case PushState:
+		writeBinaryOpen(b, OpPushState, 2)
+		writeBinaryStringValue(b, d.URL)
+		writeBinaryStringValue(b, d.Title)
+	case ReplaceState:
+		writeBinaryOpen(b, OpReplaceState, 2)
+		writeBinaryStringValue(b, d.URL)
+		writeBinaryStringValue(b, d.Title)
+	case Redirect:
+		writeBinaryOpen(b, OpRedirect, 1)
+		writeBinaryStringValue(b, d.URL)
+
+	case DefineTemplate:
+		writeBinaryOpen(b, OpDefineTemplate, 2)
+		writeBinaryStringValue(b, d.Name)
+		writeBinaryStringValue(b, d.HTMLSource.String())
+	case UseTemplate:
+		writeBinaryOpen(b, OpUseTemplate, 2)
+		writeBinaryStringValue(b, d.Name)
+		writeBinaryStrMap(b, d.Params)
+	case ReplaceText:
+		writeBinaryOpen(b, OpReplaceText, 3)
+		writeBinaryStringValue(b, d.Find)
+		writeBinaryStringValue(b, d.Replace)
+		writeBinaryBool(b, d.Regex)
+
+	default:
+		deltaJSON, err := delta.MarshalJSON()
+		if err != nil {
+			return err
+		}
+
+		return transcodeBinary(b, deltaJSON)
+	}
+
+	return nil
+}
+
+func writeUvarint(b *bytes.Buffer, x uint64) {
+	var scratch [binary.MaxVarintLen64]byte
+	b.Write(scratch[:binary.PutUvarint(scratch[:], x)])
+}
+
+func writeVarint(b *bytes.Buffer, x int64) {
+	var scratch [binary.MaxVarintLen64]byte
+	b.Write(scratch[:binary.PutVarint(scratch[:], x)])
+}
+
+func writeBinaryString(b *bytes.Buffer, str string) {
+	writeUvarint(b, uint64(len(str)))
+	b.WriteString(str)
+}
+
+func binaryLabel(value interface{}) (uint64, bool) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return 0, false
+	}
+
+	label, err := strconv.ParseUint(string(number), 10, 64)
+	return label, err == nil
+}
+
+func writeBinaryValue(b *bytes.Buffer, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		b.WriteByte(binaryNull)
+
+	case bool:
+		writeBinaryBool(b, v)
+
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			b.WriteByte(binaryInt)
+			writeVarint(b, i)
+			break
+		}
+
+		f, err := v.Float64()
+		if err != nil {
+			return err
+		}
+
+		var scratch [8]byte
+		binary.LittleEndian.PutUint64(scratch[:], math.Float64bits(f))
+		b.WriteByte(binaryFloat)
+		b.Write(scratch[:])
+
+	case string:
+		b.WriteByte(binaryString)
+		writeBinaryString(b, v)
+
+	case []interface{}:
+		values := v
+		if label, ok := binaryLabel(firstValue(v)); ok {
+			b.WriteByte(binaryDelta)
+			writeUvarint(b, label)
+			values = v[1:]
+		} else {
+			b.WriteByte(binaryArray)
+		}
+
+		writeUvarint(b, uint64(len(values)))
+		for _, item := range values {
+			if err := writeBinaryValue(b, item); err != nil {
+				return err
+			}
+		}
+
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		b.WriteByte(binaryObject)
+		writeUvarint(b, uint64(len(keys)))
+		for _, key := range keys {
+			writeBinaryString(b, key)
+			if err := writeBinaryValue(b, v[key]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func firstValue(values []interface{}) interface{} {
+	if len(values) == 0 {
+		return nil
+	}
+
+	return values[0]
+}
+
+type binaryReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+func readBinaryString(r binaryReader) (string, error) {
+	length, err := binary.ReadUvarint(r)
+	if err != nil {
+		return "", err
+	}
+
+	var b bytes.Buffer
+	if _, err := io.CopyN(&b, r, int64(length)); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+func readBinaryValues(r binaryReader, values []interface{}, depth int) ([]interface{}, error) {
+	count, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := uint64(0); i < count; i++ {
+		value, err := readBinaryValue(r, depth+1)
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+// readBinaryValue reads a value with the same shape encoding/json would
+// produce when decoding into an interface{}
+func readBinaryValue(r binaryReader, depth int) (interface{}, error) {
+	tag, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	return readTaggedBinaryValue(r, tag, depth)
+}
+
+func readTaggedBinaryValue(r binaryReader, tag byte, depth int) (interface{}, error) {
+	if depth > maxBinaryDepth {
+		return nil, errBinaryTooDeep
+	}
+
+	switch tag {
+	case binaryNull:
+		return nil, nil
+
+	case binaryFalse:
+		return false, nil
+
+	case binaryTrue:
+		return true, nil
+
+	case binaryInt:
+		i, err := binary.ReadVarint(r)
+		return float64(i), err
+
+	case binaryFloat:
+		var scratch [8]byte
+		if _, err := io.ReadFull(r, scratch[:]); err != nil {
+			return nil, err
+		}
+
+		return math.Float64frombits(binary.LittleEndian.Uint64(scratch[:])), nil
+
+	case binaryString:
+		return readBinaryString(r)
+
+	case binaryArray:
+		return readBinaryValues(r, []interface{}{}, depth)
+
+	case binaryObject:
+		count, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+
+		object := map[string]interface{}{}
+		for i := uint64(0); i < count; i++ {
+			key, err := readBinaryString(r)
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := readBinaryValue(r, depth+1)
+			if err != nil {
+				return nil, err
+			}
+
+			object[key] = value
+		}
+
+		return object, nil
+
+	case binaryDelta:
+		label, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+
+		return readBinaryValues(r, []interface{}{float64(label)}, depth)
+	}
+
+	return nil, errors.New("wit: unknown binary tag " + strconv.Itoa(int(tag)))
+}
+
+// readBinaryDelta reads the next top-level delta, returning io.EOF only if
+// the reader ends right before it
+func readBinaryDelta(r binaryReader) ([]interface{}, error) {
+	tag, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := readTaggedBinaryValue(r, tag, 0)
+	if err != nil {
+		return nil, unexpectedEOF(err)
+	}
+
+	input, ok := value.([]interface{})
+	if !ok {
+		return nil, errors.New("wit: binary value is not a delta")
+	}
+
+	return input, nil
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
+
+// UnmarshalBinary sets *l to the list of deltas encoded in the binary format
+func (l *List) UnmarshalBinary(data []byte) error {
+	input, err := readBinaryDelta(bytes.NewReader(data))
+	if err != nil {
+		return unexpectedEOF(err)
+	}
+
+	switch delta := unmarshalJSONDelta(input).(type) {
+	case List:
+		l.Deltas = delta.Deltas
+	case nil:
+	default:
+		l.Deltas = []Delta{delta}
+	}
+
+	return nil
+}
+
+// unmarshalBinary decodes the binary-encoded delta into the one target points
+// to, which must be of the same type
+func unmarshalBinary(data []byte, target interface{}) error {
+	input, err := readBinaryDelta(bytes.NewReader(data))
+	if err != nil {
+		return unexpectedEOF(err)
+	}
+
+	delta, err := decodeJSONDelta(input, "$", decodeOptions{true, ProtocolVersion})
+	if err != nil {
+		return err
+	}
+
+	value := reflect.ValueOf(target).Elem()
+	if reflect.TypeOf(delta) != value.Type() {
+		return errors.New("wit: binary payload doesn't hold a " + value.Type().Name() + " delta")
+	}
+
+	value.Set(reflect.ValueOf(delta))
+	return nil
+}
+
+// BinaryEncoder writes deltas to an output stream in the binary format
+type BinaryEncoder struct {
+	w       io.Writer
+	version int
+}
+
+// NewBinaryEncoder returns a new encoder that writes to w
+func NewBinaryEncoder(w io.Writer) *BinaryEncoder {
+	return &BinaryEncoder{w, ProtocolVersion}
+}
+
+// Version sets the protocol version spoken by the peer, see Encoder.Version
+func (e *BinaryEncoder) Version(version int) {
+	e.version = version
+}
+
+// Encode writes the binary encoding of the delta to the stream
+func (e *BinaryEncoder) Encode(delta Delta) error {
+	if err := checkVersion(delta, e.version); err != nil {
+		return err
+	}
+
+	data, err := marshalBinary(delta)
+	if err != nil {
+		return err
+	}
+
+	_, err = e.w.Write(data)
+	return err
+}
+
+// BinaryDecoder reads a stream of deltas encoded in the binary format
+type BinaryDecoder struct {
+	r    binaryReader
+	opts decodeOptions
+}
+
+// NewBinaryDecoder returns a new decoder that reads from r
+func NewBinaryDecoder(r io.Reader) *BinaryDecoder {
+	if br, ok := r.(binaryReader); ok {
+		return &BinaryDecoder{br, decodeOptions{false, ProtocolVersion}}
+	}
+
+	return &BinaryDecoder{bufio.NewReader(r), decodeOptions{false, ProtocolVersion}}
+}
+
+// Strict makes the decoder return a *DecodeError for malformed deltas
+// instead of skipping them, see UnmarshalStrict
+func (d *BinaryDecoder) Strict() {
+	d.opts.strict = true
+}
+
+// Version sets the protocol version spoken by the peer, see Decoder.Version
+func (d *BinaryDecoder) Version(version int) {
+	d.opts.version = version
+}
+
+// Decode reads the next delta from the stream, see Decoder.Decode
+func (d *BinaryDecoder) Decode() (Delta, error) {
+	for {
+		input, err := readBinaryDelta(d.r)
+		if err != nil {
+			return nil, err
+		}
+
+		delta, err := decodeJSONDelta(input, "$", d.opts)
+		if err != nil || delta != nil {
+			return delta, err
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package wit
+
+import (
+	"bytes"
+	"encoding"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBinary(t *testing.T) {
+	data, err := delta.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(data) >= len(expectedJSON) {
+		t.Error("Expected binary encoding to be smaller than ", len(expectedJSON), ", got", len(data))
+	}
+
+	var result List
+	if err := (&result).UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	resultJSON, _ := result.MarshalJSON()
+	if string(resultJSON) != expectedJSON {
+		t.Error("Expected ", expectedJSON, ", got", string(resultJSON))
+	}
+}
+
+func TestBinaryDecoder(t *testing.T) {
+	var b bytes.Buffer
+	deltas := []Delta{
+		delta,
+		DispatchEvent{"update", []byte(`{"none":null,"ok":true,"points":[1,-2],"ratio":0.5}`), true},
+	}
+
+	encoder := NewBinaryEncoder(&b)
+	for _, d := range deltas {
+		encoder.Encode(d)
+	}
+
+	decoder := NewBinaryDecoder(&b)
+	for _, d := range deltas {
+		expected, _ := d.MarshalJSON()
+
+		result, err := decoder.Decode()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resultJSON, _ := result.MarshalJSON()
+		if string(resultJSON) != string(expected) {
+			t.Error("Expected ", string(expected), ", got", string(resultJSON))
+		}
+	}
+
+	if _, err := decoder.Decode(); err != io.EOF {
+		t.Error("Expected EOF, got", err)
+	}
+}
+
+func TestBinaryMalformed(t *testing.T) {
+	data, _ := delta.MarshalBinary()
+
+	for i := 0; i < len(data); i++ {
+		var result List
+		if err := (&result).UnmarshalBinary(data[:i]); err == nil {
+			t.Error("Expected error for truncated input of length", i)
+		}
+	}
+
+	var result List
+	if err := (&result).UnmarshalBinary([]byte{binaryString, 0xff, 0xff, 0xff, 0xff, 0x0f}); err == nil {
+		t.Error("Expected error for oversized string")
+	}
+}
+
+// everyDelta holds at least one delta of each standard op
+var everyDelta = List{[]Delta{
+	Root{List{[]Delta{
+		First{S("body"), List{[]Delta{
+			HTML{HTMLFromString("<p>x</p>")},
+			Append{HTMLFromString("a")},
+			Prepend{HTMLFromString("b")},
+			Replace{HTMLFromString("c")},
+			InsertAfter{HTMLFromString("d")},
+			InsertBefore{HTMLFromString("e")},
+			Clear{},
+			Remove{},
+		}}},
+		All{S("li"), FirstChild{LastChild{PrevSibling{NextSibling{Parent{List{[]Delta{
+			SetAttr{map[string]string{"a": "1", "b": "2"}},
+			ReplaceAttr{map[string]string{"c": "3"}},
+			RmAttr{[]string{"x", "y"}},
+			SetStyles{map[string]string{"color": "red"}},
+			RmStyles{[]string{"color"}},
+			AddClasses{"a b"},
+			RmClasses{"c"},
+			AddTokens{"rel", "next"},
+			RmTokens{"rel", "prev"},
+		}}}}}}}},
+	}}},
+	LoadScript{"/a.js", map[string]string{"defer": ""}},
+	LoadStylesheet{"/a.css", nil},
+	ReconcileChildren{[]KeyedChild{{"a", HTMLFromString("<li>A</li>")}, {"b", nil}}},
+	Focus{},
+	Blur{},
+	ScrollIntoView{map[string]string{"block": "center"}},
+	ScrollTo{-1, 2},
+	SelectText{0, 3},
+	DispatchEvent{"x", []byte(`{"n": [1, 2.5, true, null], "s": "v"}`), true},
+	Delay{300 * time.Millisecond, List{[]Delta{Remove{}, List{[]Delta{Blur{}}}}}},
+	RemoveWithTransition{"fade out", time.Second},
+	AppendWithTransition{HTMLFromString("<i></i>"), "in", 0},
+	InsertAfterWithTransition{HTMLFromString("<i></i>"), "in", time.Millisecond},
+	PushState{"/a", "A"},
+	ReplaceState{"/b", ""},
+	Redirect{"/c"},
+	Reload{},
+	DefineTemplate{"row", HTMLFromString("<li>{{name}}</li>")},
+	UseTemplate{"row", map[string]string{"name": "n"}},
+	ReplaceText{"a(b)", "$1", true},
+	Range{"items", Clear{}},
+	MergeHead{HTMLFromString("<title>x</title>")},
+}}
+
+func TestBinaryDirect(t *testing.T) {
+	ops := map[Op]bool{}
+	Inspect(everyDelta, func(delta Delta) bool {
+		if delta != nil {
+			ops[OpOf(delta)] = true
+		}
+
+		return true
+	})
+
+	for op := range labels {
+		if !ops[op] {
+			t.Error("Expected everyDelta to hold", op)
+		}
+	}
+
+	for _, d := range []Delta{everyDelta, chartUpdate{"sales"}, First{S("#chart"), chartUpdate{"sales"}}} {
+		data, err := marshalBinary(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		deltaJSON, _ := d.MarshalJSON()
+		var transcoded bytes.Buffer
+		if err := transcodeBinary(&transcoded, deltaJSON); err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(data, transcoded.Bytes()) {
+			t.Error("Expected ", transcoded.Bytes(), " for ", string(deltaJSON), ", got", data)
+		}
+	}
+}
+
+func TestUnmarshalBinary(t *testing.T) {
+	Inspect(everyDelta, func(d Delta) bool {
+		if d == nil {
+			return false
+		}
+
+		data, _ := d.(encoding.BinaryMarshaler).MarshalBinary()
+		result := reflect.New(reflect.TypeOf(d))
+		if err := result.Interface().(encoding.BinaryUnmarshaler).UnmarshalBinary(data); err != nil {
+			t.Error(err)
+			return true
+		}
+
+		expected, _ := d.MarshalJSON()
+		resultJSON, _ := result.Elem().Interface().(Delta).MarshalJSON()
+		if string(resultJSON) != string(expected) {
+			t.Error("Expected ", string(expected), ", got", string(resultJSON))
+		}
+
+		return true
+	})
+
+	data, _ := Remove{}.MarshalBinary()
+	var result Clear
+	if err := (&result).UnmarshalBinary(data); err == nil || err.Error() != "wit: binary payload doesn't hold a Clear delta" {
+		t.Error("Expected type mismatch error, got", err)
+	}
+}
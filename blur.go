@@ -0,0 +1,27 @@
+package wit
+
+// Blur removes focus from matching elements. It only has effect on the client.
+type Blur struct{}
+
+// Apply applies the delta to the provided elements
+func (b Blur) Apply(d Document) {}
+
+// MarshalJSON marshals the delta to JSON format
+func (b Blur) MarshalJSON() ([]byte, error) {
+	return []byte("[" + blurLabelJSON + "]"), nil
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (b Blur) MarshalBinary() ([]byte, error) {
+	return marshalBinary(b)
+}
+
+// UnmarshalBinary sets *b to the delta encoded in the binary format
+func (b *Blur) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, b)
+}
+
+// Op returns the operation performed by the delta
+func (b Blur) Op() Op {
+	return OpBlur
+}
@@ -8,6 +8,11 @@ type Clear struct{}
 // Apply applies the delta to the provided elements
 func (c Clear) Apply(d Document) {
 	for _, node := range d.nodes {
+		if isRangeStart(node) {
+			clearRange(node)
+			continue
+		}
+
 		if node.Type == html.ElementNode {
 			for node.FirstChild != nil {
 				node.RemoveChild(node.FirstChild)
@@ -20,3 +25,18 @@ func (c Clear) Apply(d Document) {
 func (c Clear) MarshalJSON() ([]byte, error) {
 	return []byte("[" + clearLabelJSON + "]"), nil
 }
+
+// MarshalBinary marshals the delta to the binary format
+func (c Clear) MarshalBinary() ([]byte, error) {
+	return marshalBinary(c)
+}
+
+// UnmarshalBinary sets *c to the delta encoded in the binary format
+func (c *Clear) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, c)
+}
+
+// Op returns the operation performed by the delta
+func (c Clear) Op() Op {
+	return OpClear
+}
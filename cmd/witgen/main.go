@@ -0,0 +1,34 @@
+// Command witgen writes the JSON Schema and TypeScript definitions
+// describing the wit wire format
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"log"
+
+	"github.com/manvalls/wit"
+)
+
+func main() {
+	schemaPath := flag.String("schema", "", "path of the generated JSON Schema")
+	tsPath := flag.String("ts", "", "path of the generated TypeScript definitions")
+	flag.Parse()
+
+	if *schemaPath != "" {
+		schema, err := wit.JSONSchema()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if err := ioutil.WriteFile(*schemaPath, append(schema, '\n'), 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if *tsPath != "" {
+		if err := ioutil.WriteFile(*tsPath, []byte(wit.TypeScript()), 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,941 @@
+package wit
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// jsNodes describes what the nodes held by a compiled variable are
+type jsNodes int
+
+const (
+	jsElements jsNodes = iota
+	jsDocument
+	jsRanges
+)
+
+// jsHelpers holds the functions compiled code may call, they are only
+// emitted when used
+var jsHelpers = map[string]string{
+	"first": `function first(nodes, selector) {
+  var result = [];
+  nodes.forEach(function (n) {
+    var match = n.querySelector && n.querySelector(selector);
+    if (match) result.push(match);
+  });
+  return result;
+}`,
+	"all": `function all(nodes, selector) {
+  var result = [];
+  nodes.forEach(function (n) {
+    if (n.querySelectorAll) result.push.apply(result, n.querySelectorAll(selector));
+  });
+  return result;
+}`,
+	"step": `function step(nodes, property) {
+  var result = [];
+  nodes.forEach(function (n) {
+    if (n[property]) result.push(n[property]);
+  });
+  return result;
+}`,
+	"fragment": `function fragment(html) {
+  var template = doc.createElement("template");
+  template.innerHTML = html;
+  return template.content;
+}`,
+	"rangeStart": `function rangeStart(nodes, name) {
+  var result = [];
+  nodes.forEach(function (n) {
+    var walker = doc.createTreeWalker(n, NodeFilter.SHOW_COMMENT);
+    for (var c = walker.nextNode(); c; c = walker.nextNode()) {
+      if (c.data.trim() === "wit:start " + name) {
+        result.push(c);
+        return;
+      }
+    }
+  });
+  return result;
+}`,
+	"rangeEnd": `function rangeEnd(start) {
+  var end = "wit:end " + start.data.trim().slice("wit:start ".length);
+  for (var n = start.nextSibling; n; n = n.nextSibling) {
+    if (n.nodeType === 8 && n.data.trim() === end) return n;
+  }
+  return null;
+}`,
+	"rangeClear": `function rangeClear(start) {
+  if (!start.parentNode) return;
+  var end = rangeEnd(start);
+  while (start.nextSibling && start.nextSibling !== end) start.parentNode.removeChild(start.nextSibling);
+}`,
+	"rangeInsert": `function rangeInsert(start, html, atEnd) {
+  if (!start.parentNode) return;
+  start.parentNode.insertBefore(fragment(html), atEnd ? rangeEnd(start) : start.nextSibling);
+}`,
+	"addTokens": `function addTokens(e, attr, tokens) {
+  var list = (e.getAttribute(attr) || "").split(/\s+/).filter(Boolean);
+  tokens.forEach(function (t) {
+    if (list.indexOf(t) < 0) list.push(t);
+  });
+  e.setAttribute(attr, list.join(" "));
+}`,
+	"rmTokens": `function rmTokens(e, attr, tokens) {
+  if (!e.hasAttribute(attr)) return;
+  e.setAttribute(attr, e.getAttribute(attr).split(/\s+/).filter(function (t) {
+    return t && tokens.indexOf(t) < 0;
+  }).join(" "));
+}`,
+	"afterTransition": `function afterTransition(e, timeout, f) {
+  var done = false;
+  var finish = function () {
+    if (done) return;
+    done = true;
+    e.removeEventListener("transitionend", finish);
+    f();
+  };
+  e.addEventListener("transitionend", finish);
+  setTimeout(finish, timeout);
+}`,
+	"insertWithTransition": `function insertWithTransition(parent, before, html, classes, timeout) {
+  var content = fragment(html);
+  var nodes = [].slice.call(content.children);
+  nodes.forEach(function (n) {
+    n.classList.add.apply(n.classList, classes);
+  });
+  parent.insertBefore(content, before);
+  nodes.forEach(function (n) {
+    afterTransition(n, timeout, function () {
+      n.classList.remove.apply(n.classList, classes);
+    });
+  });
+}`,
+	"reconcile": `function reconcile(e, children) {
+  var existing = {};
+  [].slice.call(e.children).forEach(function (c) {
+    var key = c.getAttribute("data-key");
+    if (key !== null && !existing.hasOwnProperty(key)) existing[key] = c;
+  });
+  var result = [];
+  children.forEach(function (child) {
+    if (existing.hasOwnProperty(child[0])) {
+      result.push(existing[child[0]]);
+      delete existing[child[0]];
+    } else if (child[1] !== null) {
+      var n = fragment(child[1]).firstElementChild;
+      if (n) {
+        n.setAttribute("data-key", child[0]);
+        result.push(n);
+      }
+    }
+  });
+  e.textContent = "";
+  result.forEach(function (n) {
+    e.appendChild(n);
+  });
+}`,
+	"useTemplate": `function useTemplate(e, name, params) {
+  if (!templates.hasOwnProperty(name)) return;
+  var content = fragment(templates[name]);
+  var fill = function (s) {
+    return s.replace(/\{\{\s*([^{}\s]+)\s*\}\}/g, function (m, key) {
+      return params.hasOwnProperty(key) ? params[key] : "";
+    });
+  };
+  var walker = doc.createTreeWalker(content, NodeFilter.SHOW_ELEMENT | NodeFilter.SHOW_TEXT);
+  for (var n = walker.nextNode(); n; n = walker.nextNode()) {
+    if (n.nodeType === 3) {
+      if (!isRawText(n.parentNode)) n.data = fill(n.data);
+      continue;
+    }
+    for (var i = 0; i < n.attributes.length; i++) n.attributes[i].value = fill(n.attributes[i].value);
+  }
+  e.appendChild(content);
+}`,
+	"load": `function load(selector, tag, attrs, f) {
+  f = f || function () {};
+  var e = doc.querySelector(selector);
+  if (!e) {
+    if (!doc.head) return f();
+    e = doc.createElement(tag);
+    for (var key in attrs) e.setAttribute(key, attrs[key]);
+    e.witPending = [];
+    var done = function () {
+      var pending = e.witPending || [];
+      e.witPending = null;
+      pending.forEach(function (g) { g(); });
+    };
+    e.addEventListener("load", done);
+    e.addEventListener("error", done);
+    doc.head.appendChild(e);
+  }
+  if (e.witPending) e.witPending.push(f);
+  else f();
+}`,
+	"isRawText": `function isRawText(e) {
+  return /^(SCRIPT|STYLE|IFRAME|NOEMBED|NOFRAMES|NOSCRIPT|PLAINTEXT|XMP)$/.test(e.nodeName);
+}`,
+	"replaceText": `function replaceText(e, find, replace) {
+  var walker = doc.createTreeWalker(e, NodeFilter.SHOW_TEXT);
+  for (var n = walker.nextNode(); n; n = walker.nextNode()) {
+    if (isRawText(n.parentNode)) continue;
+    n.data = typeof find === "string" ? n.data.split(find).join(replace) : n.data.replace(find, replace);
+  }
+}`,
+}
+
+// jsHelperDeps lists the helpers each helper calls
+var jsHelperDeps = map[string][]string{
+	"rangeClear":           {"rangeEnd"},
+	"rangeInsert":          {"rangeEnd", "fragment"},
+	"insertWithTransition": {"fragment", "afterTransition"},
+	"reconcile":            {"fragment"},
+	"useTemplate":          {"fragment", "isRawText"},
+	"replaceText":          {"isRawText"},
+}
+
+type jsCompiler struct {
+	b         bytes.Buffer
+	depth     int
+	vars      int
+	helpers   map[string]bool
+	templates bool
+	loads     []jsLoad
+}
+
+// jsLoad is a load callback still open, it holds the call and where it
+// starts and ends in the output so it can be removed if left empty
+type jsLoad struct {
+	call       string
+	start, end int
+}
+
+// CompileJS returns the source of a self-contained JavaScript function
+// performing the DOM operations of the provided delta. The function takes
+// the document to operate on, which defaults to the global one, so it can
+// be inlined in a script element:
+//
+//	"<script>(" + wit.CompileJS(delta) + ")()</script>"
+//
+// Custom deltas and deltas which can't be performed by plain JavaScript
+// are compiled to comments. Events dispatched on the document itself are
+// dispatched on it, while other effects target its window, root or active
+// element. Templates are only visible to the deltas compiled along with
+// them. The deltas following a LoadScript or LoadStylesheet only run once it
+// has loaded, and when it's within a Delay, this only holds until the end
+// of the Delay.
+func CompileJS(delta Delta) string {
+	c := &jsCompiler{depth: 1, helpers: map[string]bool{}}
+	c.line("var n0 = [doc];")
+	c.compile(delta, "n0", jsDocument)
+	c.closeLoads(0)
+
+	var b strings.Builder
+	b.WriteString("function (doc) {\n  doc = doc || document;\n")
+	if c.templates {
+		b.WriteString("  var templates = {};\n")
+	}
+
+	b.WriteString(c.b.String())
+
+	names := make([]string, 0, len(c.helpers))
+	for name := range c.helpers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	for _, name := range names {
+		b.WriteString("\n  " + strings.ReplaceAll(jsHelpers[name], "\n", "\n  ") + "\n")
+	}
+
+	b.WriteString("}")
+	return b.String()
+}
+
+func (c *jsCompiler) line(code string) {
+	c.b.WriteString(strings.Repeat("  ", c.depth) + code + "\n")
+}
+
+func (c *jsCompiler) use(helper string) string {
+	c.helpers[helper] = true
+	for _, dep := range jsHelperDeps[helper] {
+		c.use(dep)
+	}
+
+	return helper
+}
+
+func (c *jsCompiler) newVar() string {
+	c.vars++
+	return "n" + strconv.Itoa(c.vars)
+}
+
+// traverse assigns the result of the provided expression to a new variable
+// and compiles the child delta against it
+func (c *jsCompiler) traverse(child Delta, expr string, kind jsNodes) {
+	v := c.newVar()
+	c.line("var " + v + " = " + expr + ";")
+	c.compile(child, v, kind)
+}
+
+// each runs the provided statements for every node
+func (c *jsCompiler) each(nodes string, statements ...string) {
+	if len(statements) == 1 {
+		c.line(nodes + ".forEach(function (e) { " + statements[0] + " });")
+		return
+	}
+
+	c.line(nodes + ".forEach(function (e) {")
+	for _, statement := range statements {
+		c.line("  " + statement)
+	}
+
+	c.line("});")
+}
+
+func (c *jsCompiler) compile(delta Delta, nodes string, kind jsNodes) {
+	if delta == nil {
+		return
+	}
+
+	switch d := delta.(type) {
+	case List:
+		for _, child := range d.Deltas {
+			c.compile(child, nodes, kind)
+		}
+
+		return
+	case Root:
+		c.traverse(d.Delta, "[doc]", jsDocument)
+		return
+	case First:
+		c.traverse(d.Delta, c.use("first")+"("+nodes+", "+jsString(d.Selector.String())+")", jsElements)
+		return
+	case All:
+		c.traverse(d.Delta, c.use("all")+"("+nodes+", "+jsString(d.Selector.String())+")", jsElements)
+		return
+	case Parent:
+		c.traverse(d.Delta, c.use("step")+"("+nodes+", \"parentNode\")", jsElements)
+		return
+	case FirstChild:
+		c.traverse(d.Delta, c.use("step")+"("+nodes+", \"firstElementChild\")", jsElements)
+		return
+	case LastChild:
+		c.traverse(d.Delta, c.use("step")+"("+nodes+", \"lastElementChild\")", jsElements)
+		return
+	case PrevSibling:
+		c.traverse(d.Delta, c.use("step")+"("+nodes+", \"previousElementSibling\")", jsElements)
+		return
+	case NextSibling:
+		c.traverse(d.Delta, c.use("step")+"("+nodes+", \"nextElementSibling\")", jsElements)
+		return
+	case Range:
+		c.traverse(d.Delta, c.use("rangeStart")+"("+nodes+", "+jsString(d.Name)+")", jsRanges)
+		return
+	case Delay:
+		c.line("setTimeout(function () {")
+		c.depth++
+		loads := len(c.loads)
+		c.compile(d.Delta, nodes, kind)
+		c.closeLoads(loads)
+		c.depth--
+		c.line("}, " + jsMillis(d.Duration) + ");")
+		return
+	case Remove:
+		c.each(nodes, "if (e.parentNode) e.parentNode.removeChild(e);")
+		return
+
+	case LoadScript, LoadStylesheet:
+		c.load(delta)
+		return
+	case DefineTemplate:
+		c.templates = true
+		c.line("templates[" + jsString(d.Name) + "] = " + jsHTML(d.HTMLSource) + ";")
+		return
+	case PushState:
+		c.line("history.pushState(null, " + jsString(d.Title) + ", " + jsString(d.URL) + ");")
+		return
+	case ReplaceState:
+		c.line("history.replaceState(null, " + jsString(d.Title) + ", " + jsString(d.URL) + ");")
+		return
+	case Redirect:
+		c.line("location.href = " + jsString(d.URL) + ";")
+		return
+	case Reload:
+		c.line("location.reload();")
+		return
+	}
+
+	op := OpOf(delta)
+	if _, ok := labels[op]; !ok || op == OpMergeHead {
+		c.line("// " + op.String() + " is not supported")
+		return
+	}
+
+	if kind == jsRanges {
+		c.compileRange(delta, nodes)
+		return
+	}
+
+	if kind == jsDocument {
+		c.compileDocument(delta, nodes)
+		return
+	}
+
+	switch d := delta.(type) {
+	case Clear:
+		c.each(nodes, "e.textContent = \"\";")
+	case HTML:
+		c.each(nodes, "e.innerHTML = "+jsHTML(d.HTMLSource)+";")
+	case Replace:
+		c.each(nodes, "if (e.parentNode) e.outerHTML = "+jsHTML(d.HTMLSource)+";")
+	case Append:
+		c.each(nodes, "e.insertAdjacentHTML(\"beforeend\", "+jsHTML(d.HTMLSource)+");")
+	case Prepend:
+		c.each(nodes, "e.insertAdjacentHTML(\"afterbegin\", "+jsHTML(d.HTMLSource)+");")
+	case InsertAfter:
+		c.each(nodes, "if (e.parentNode) e.insertAdjacentHTML(\"afterend\", "+jsHTML(d.HTMLSource)+");")
+	case InsertBefore:
+		c.each(nodes, "if (e.parentNode) e.insertAdjacentHTML(\"beforebegin\", "+jsHTML(d.HTMLSource)+");")
+
+	case SetAttr:
+		statements := []string{}
+		for _, key := range sortedKeys(d.Attributes) {
+			statements = append(statements, "e.setAttribute("+jsString(key)+", "+jsString(d.Attributes[key])+");")
+		}
+
+		c.eachIfAny(nodes, statements)
+	case ReplaceAttr:
+		statements := []string{"while (e.attributes.length) e.removeAttribute(e.attributes[0].name);"}
+		for _, key := range sortedKeys(d.Attributes) {
+			statements = append(statements, "e.setAttribute("+jsString(key)+", "+jsString(d.Attributes[key])+");")
+		}
+
+		c.each(nodes, statements...)
+	case RmAttr:
+		statements := []string{}
+		for _, key := range d.Attributes {
+			statements = append(statements, "e.removeAttribute("+jsString(key)+");")
+		}
+
+		c.eachIfAny(nodes, statements)
+	case SetStyles:
+		statements := []string{}
+		for _, key := range sortedKeys(d.Styles) {
+			value, priority := splitImportant(d.Styles[key])
+			args := jsString(key) + ", " + jsString(value)
+			if priority != "" {
+				args += ", " + jsString(priority)
+			}
+
+			statements = append(statements, "e.style.setProperty("+args+");")
+		}
+
+		c.eachIfAny(nodes, statements)
+	case RmStyles:
+		statements := []string{}
+		for _, key := range d.Styles {
+			statements = append(statements, "e.style.removeProperty("+jsString(key)+");")
+		}
+
+		c.eachIfAny(nodes, statements)
+	case AddClasses:
+		c.tokens(nodes, "class", d.Classes, true)
+	case RmClasses:
+		c.tokens(nodes, "class", d.Classes, false)
+	case AddTokens:
+		c.tokens(nodes, d.Attr, d.Tokens, true)
+	case RmTokens:
+		c.tokens(nodes, d.Attr, d.Tokens, false)
+
+	case ReconcileChildren:
+		children := make([]string, len(d.Children))
+		for i, child := range d.Children {
+			source := "null"
+			if child.HTMLSource != nil {
+				source = jsHTML(child.HTMLSource)
+			}
+
+			children[i] = "[" + jsString(child.Key) + ", " + source + "]"
+		}
+
+		c.each(nodes, c.use("reconcile")+"(e, ["+strings.Join(children, ", ")+"]);")
+
+	case Focus:
+		c.line("if (" + nodes + "[0]) " + nodes + "[0].focus();")
+	case Blur:
+		c.each(nodes, "e.blur();")
+	case ScrollIntoView:
+		c.line("if (" + nodes + "[0]) " + nodes + "[0].scrollIntoView(" + jsObject(d.Options) + ");")
+	case ScrollTo:
+		c.each(nodes, "e.scrollTop = "+strconv.Itoa(d.Top)+";", "e.scrollLeft = "+strconv.Itoa(d.Left)+";")
+	case SelectText:
+		c.line("if (" + nodes + "[0]) " + nodes + "[0].setSelectionRange(" + strconv.Itoa(d.Start) + ", " + strconv.Itoa(d.End) + ");")
+	case DispatchEvent:
+		c.dispatchEvent(d, nodes)
+
+	case RemoveWithTransition:
+		statements := []string{}
+		if classes := parseTokens(d.Classes); len(classes) != 0 {
+			statements = append(statements, "e.classList.add("+jsStrings(classes)+");")
+		}
+
+		statements = append(statements, c.use("afterTransition")+"(e, "+jsMillis(d.Timeout)+", function () { if (e.parentNode) e.parentNode.removeChild(e); });")
+		c.each(nodes, statements...)
+	case AppendWithTransition:
+		c.each(nodes, c.use("insertWithTransition")+"(e, null, "+jsHTML(d.HTMLSource)+", ["+jsStrings(parseTokens(d.Classes))+"], "+jsMillis(d.Timeout)+");")
+	case InsertAfterWithTransition:
+		c.each(nodes, "if (e.parentNode) "+c.use("insertWithTransition")+"(e.parentNode, e.nextSibling, "+jsHTML(d.HTMLSource)+", ["+jsStrings(parseTokens(d.Classes))+"], "+jsMillis(d.Timeout)+");")
+
+	case UseTemplate:
+		c.templates = true
+		c.each(nodes, c.use("useTemplate")+"(e, "+jsString(d.Name)+", "+jsObject(d.Params)+");")
+	case ReplaceText:
+		if d.Find == "" {
+			return
+		}
+
+		find, replace := jsString(d.Find), jsString(d.Replace)
+		if d.Regex {
+			pattern, template, ok := jsRegExp(d.Find, d.Replace)
+			if !ok {
+				c.line("// ReplaceText has a regular expression which can't be compiled")
+				return
+			}
+
+			find, replace = "new RegExp("+jsString(pattern)+", \"gu\")", jsString(template)
+		}
+
+		c.each(nodes, c.use("replaceText")+"(e, "+find+", "+replace+");")
+
+	default:
+		c.line("// " + op.String() + " is not supported")
+	}
+}
+
+// compileDocument compiles deltas applied to documents, effects which
+// documents can't take are performed on their window or active element
+func (c *jsCompiler) compileDocument(delta Delta, nodes string) {
+	switch d := delta.(type) {
+	case Focus:
+		c.each(nodes, "(e.defaultView || window).focus();")
+	case Blur:
+		c.each(nodes, "if (e.activeElement) e.activeElement.blur();")
+	case ScrollIntoView:
+		c.each(nodes, "if (e.documentElement) e.documentElement.scrollIntoView("+jsObject(d.Options)+");")
+	case ScrollTo:
+		c.each(nodes, "(e.defaultView || window).scrollTo("+strconv.Itoa(d.Left)+", "+strconv.Itoa(d.Top)+");")
+	case SelectText:
+		c.each(nodes, "if (e.activeElement && e.activeElement.setSelectionRange) e.activeElement.setSelectionRange("+strconv.Itoa(d.Start)+", "+strconv.Itoa(d.End)+");")
+	case DispatchEvent:
+		c.dispatchEvent(d, nodes)
+	default:
+		c.line("// " + OpOf(delta).String() + " has no effect on the document")
+	}
+}
+
+// compileRange compiles deltas applied to the starting comments of ranges
+func (c *jsCompiler) compileRange(delta Delta, nodes string) {
+	switch d := delta.(type) {
+	case Clear:
+		c.each(nodes, c.use("rangeClear")+"(e);")
+	case HTML:
+		c.each(nodes, c.use("rangeClear")+"(e);", c.use("rangeInsert")+"(e, "+jsHTML(d.HTMLSource)+", true);")
+	case Append:
+		c.each(nodes, c.use("rangeInsert")+"(e, "+jsHTML(d.HTMLSource)+", true);")
+	case Prepend:
+		c.each(nodes, c.use("rangeInsert")+"(e, "+jsHTML(d.HTMLSource)+", false);")
+	default:
+		c.line("// " + OpOf(delta).String() + " has no effect on ranges")
+	}
+}
+
+func (c *jsCompiler) dispatchEvent(d DispatchEvent, nodes string) {
+	detail := "null"
+	if len(d.Detail) != 0 {
+		var b bytes.Buffer
+		if err := json.Compact(&b, d.Detail); err != nil {
+			c.line("// DispatchEvent has an invalid detail")
+			return
+		}
+
+		var escaped bytes.Buffer
+		json.HTMLEscape(&escaped, b.Bytes())
+		detail = escaped.String()
+	}
+
+	c.each(nodes, "e.dispatchEvent(new CustomEvent("+jsString(d.Type)+", { detail: "+detail+", bubbles: "+strconv.FormatBool(d.Bubbles)+" }));")
+}
+
+func (c *jsCompiler) eachIfAny(nodes string, statements []string) {
+	if len(statements) != 0 {
+		c.each(nodes, statements...)
+	}
+}
+
+func (c *jsCompiler) tokens(nodes, attr, list string, add bool) {
+	tokens := parseTokens(list)
+	if len(tokens) == 0 {
+		return
+	}
+
+	if attr == "class" {
+		method := "remove"
+		if add {
+			method = "add"
+		}
+
+		c.each(nodes, "e.classList."+method+"("+jsStrings(tokens)+");")
+		return
+	}
+
+	helper := "rmTokens"
+	if add {
+		helper = "addTokens"
+	}
+
+	c.each(nodes, c.use(helper)+"(e, "+jsString(attr)+", ["+jsStrings(tokens)+"]);")
+}
+
+// load compiles a LoadScript or LoadStylesheet delta, the code following it
+// goes into its callback until closeLoads is called
+func (c *jsCompiler) load(delta Delta) {
+	var tag, keyAttr, key string
+	attributes := map[string]string{}
+
+	switch d := delta.(type) {
+	case LoadScript:
+		tag, keyAttr, key = "script", "src", d.Src
+		for attrKey, value := range d.Attributes {
+			attributes[attrKey] = value
+		}
+	case LoadStylesheet:
+		tag, keyAttr, key = "link", "href", d.Href
+		for attrKey, value := range d.Attributes {
+			attributes[attrKey] = value
+		}
+
+		attributes["rel"] = "stylesheet"
+	}
+
+	attributes[keyAttr] = key
+	call := c.use("load") + "(" + jsString(tag+"["+keyAttr+"="+quoteCSSString(key)+"]") + ", " + jsString(tag) + ", " + jsObject(attributes)
+	start := c.b.Len()
+	c.line(call + ", function () {")
+	c.loads = append(c.loads, jsLoad{call, start, c.b.Len()})
+	c.depth++
+}
+
+// closeLoads closes the load callbacks opened after the first n ones, the
+// ones left empty are replaced by plain calls
+func (c *jsCompiler) closeLoads(n int) {
+	for len(c.loads) > n {
+		load := c.loads[len(c.loads)-1]
+		c.loads = c.loads[:len(c.loads)-1]
+		c.depth--
+
+		if c.b.Len() == load.end {
+			c.b.Truncate(load.start)
+			c.line(load.call + ");")
+			continue
+		}
+
+		c.line("});")
+	}
+}
+
+// splitImportant splits the !important flag off a style value, since
+// setProperty ignores values carrying it and takes the priority apart
+func splitImportant(value string) (string, string) {
+	const important = "important"
+
+	trimmed := strings.TrimRightFunc(value, unicode.IsSpace)
+	if len(trimmed) < len(important) || !strings.EqualFold(trimmed[len(trimmed)-len(important):], important) {
+		return value, ""
+	}
+
+	trimmed = strings.TrimRightFunc(trimmed[:len(trimmed)-len(important)], unicode.IsSpace)
+	if !strings.HasSuffix(trimmed, "!") {
+		return value, ""
+	}
+
+	return strings.TrimRightFunc(trimmed[:len(trimmed)-1], unicode.IsSpace), important
+}
+
+// jsRegExp translates a Go regular expression and replacement template into
+// their equivalents for a JavaScript RegExp using the u flag. Patterns are
+// rewritten from their syntax tree, since Go flags, named groups and the
+// like are written differently in JavaScript, or not at all.
+func jsRegExp(pattern, template string) (string, string, bool) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", "", false
+	}
+
+	tree, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", "", false
+	}
+
+	var b strings.Builder
+	writeJSRegExp(&b, tree)
+
+	replace, ok := jsReplacement(re, template)
+	return b.String(), replace, ok
+}
+
+func writeJSRegExp(b *strings.Builder, re *syntax.Regexp) {
+	switch re.Op {
+	case syntax.OpNoMatch:
+		b.WriteString("[]")
+	case syntax.OpEmptyMatch:
+		b.WriteString("(?:)")
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			if re.Flags&syntax.FoldCase == 0 || unicode.SimpleFold(r) == r {
+				writeJSRune(b, r)
+				continue
+			}
+
+			b.WriteByte('[')
+			for f := r; ; {
+				writeJSRune(b, f)
+				if f = unicode.SimpleFold(f); f == r {
+					break
+				}
+			}
+
+			b.WriteByte(']')
+		}
+	case syntax.OpCharClass:
+		b.WriteByte('[')
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			writeJSRune(b, re.Rune[i])
+			if re.Rune[i+1] != re.Rune[i] {
+				b.WriteByte('-')
+				writeJSRune(b, re.Rune[i+1])
+			}
+		}
+
+		b.WriteByte(']')
+	case syntax.OpAnyCharNotNL:
+		b.WriteString(`[^\n]`)
+	case syntax.OpAnyChar:
+		b.WriteString(`[\s\S]`)
+	case syntax.OpBeginLine:
+		b.WriteString(`(?<=^|\n)`)
+	case syntax.OpEndLine:
+		b.WriteString(`(?=\n|$)`)
+	case syntax.OpBeginText:
+		b.WriteString("^")
+	case syntax.OpEndText:
+		b.WriteString("$")
+	case syntax.OpWordBoundary:
+		b.WriteString(`\b`)
+	case syntax.OpNoWordBoundary:
+		b.WriteString(`\B`)
+	case syntax.OpCapture:
+		b.WriteByte('(')
+		writeJSRegExp(b, re.Sub[0])
+		b.WriteByte(')')
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		b.WriteString("(?:")
+		writeJSRegExp(b, re.Sub[0])
+		b.WriteByte(')')
+
+		switch re.Op {
+		case syntax.OpStar:
+			b.WriteByte('*')
+		case syntax.OpPlus:
+			b.WriteByte('+')
+		case syntax.OpQuest:
+			b.WriteByte('?')
+		default:
+			b.WriteString("{" + strconv.Itoa(re.Min))
+			if re.Max != re.Min {
+				b.WriteByte(',')
+				if re.Max != -1 {
+					b.WriteString(strconv.Itoa(re.Max))
+				}
+			}
+
+			b.WriteByte('}')
+		}
+
+		if re.Flags&syntax.NonGreedy != 0 {
+			b.WriteByte('?')
+		}
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			writeJSRegExp(b, sub)
+		}
+	case syntax.OpAlternate:
+		b.WriteString("(?:")
+		for i, sub := range re.Sub {
+			if i != 0 {
+				b.WriteByte('|')
+			}
+
+			writeJSRegExp(b, sub)
+		}
+
+		b.WriteByte(')')
+	}
+}
+
+// writeJSRune writes the rune as is if it's alphanumeric, and as a \u{...}
+// escape otherwise, which stands for itself both inside and outside classes
+func writeJSRune(b *strings.Builder, r rune) {
+	if r < utf8.RuneSelf && (r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)) {
+		b.WriteRune(r)
+		return
+	}
+
+	b.WriteString(`\u{` + strconv.FormatInt(int64(r), 16) + "}")
+}
+
+// jsReplacement translates a template as expanded by Regexp.Expand into a
+// JavaScript replacement string. Groups are referenced using two digits so
+// they can't run into a following digit.
+func jsReplacement(re *regexp.Regexp, template string) (string, bool) {
+	var b strings.Builder
+
+	for {
+		i := strings.IndexByte(template, '$')
+		if i == -1 {
+			break
+		}
+
+		b.WriteString(template[:i])
+		template = template[i+1:]
+
+		if strings.HasPrefix(template, "$") {
+			b.WriteString("$$")
+			template = template[1:]
+			continue
+		}
+
+		name, rest, ok := extractGroupName(template)
+		if !ok {
+			b.WriteString("$$")
+			continue
+		}
+
+		template = rest
+		group := groupIndex(re, name)
+		switch {
+		case group == 0:
+			b.WriteString("$&")
+		case group > 99:
+			return "", false
+		case group > 0:
+			b.WriteString("$" + strconv.Itoa(group/10) + strconv.Itoa(group%10))
+		}
+	}
+
+	b.WriteString(strings.ReplaceAll(template, "$", "$$"))
+	return b.String(), true
+}
+
+// extractGroupName reads the group reference following a $ the same way
+// Regexp.Expand does, i.e. either name or {name}
+func extractGroupName(template string) (string, string, bool) {
+	brace := strings.HasPrefix(template, "{")
+	if brace {
+		template = template[1:]
+	}
+
+	i := 0
+	for i < len(template) {
+		r, size := utf8.DecodeRuneInString(template[i:])
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			break
+		}
+
+		i += size
+	}
+
+	if i == 0 {
+		return "", "", false
+	}
+
+	name := template[:i]
+	if brace {
+		if i >= len(template) || template[i] != '}' {
+			return "", "", false
+		}
+
+		i++
+	}
+
+	return name, template[i:], true
+}
+
+// groupIndex returns the index of the group a template references by number
+// or name, or -1 if there's no such group
+func groupIndex(re *regexp.Regexp, name string) int {
+	if number, err := strconv.Atoi(name); err == nil && number >= 0 && name[0] != '+' && (name[0] != '0' || len(name) == 1) {
+		if number <= re.NumSubexp() {
+			return number
+		}
+
+		return -1
+	}
+
+	for i, subexp := range re.SubexpNames() {
+		if subexp != "" && subexp == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// jsString quotes the provided string as a JavaScript literal which is
+// safe to inline in a script element
+func jsString(str string) string {
+	result, _ := json.Marshal(str)
+	return string(result)
+}
+
+func jsStrings(strs []string) string {
+	quoted := make([]string, len(strs))
+	for i, str := range strs {
+		quoted[i] = jsString(str)
+	}
+
+	return strings.Join(quoted, ", ")
+}
+
+func jsHTML(source HTMLSource) string {
+	if source == nil {
+		return "\"\""
+	}
+
+	return jsString(source.String())
+}
+
+func jsObject(m map[string]string) string {
+	if len(m) == 0 {
+		return "{}"
+	}
+
+	entries := []string{}
+	for _, key := range sortedKeys(m) {
+		entries = append(entries, jsString(key)+": "+jsString(m[key]))
+	}
+
+	return "{ " + strings.Join(entries, ", ") + " }"
+}
+
+func jsMillis(duration time.Duration) string {
+	return strconv.FormatInt(duration.Milliseconds(), 10)
+}
@@ -0,0 +1,182 @@
+package wit
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompileJS(t *testing.T) {
+	delta := List{[]Delta{
+		First{S("#list"), List{[]Delta{
+			Append{HTMLFromString("<li>new</li>")},
+			AddClasses{"loaded"},
+			Delay{time.Second, All{S("li.new"), RmClasses{"new"}}},
+		}}},
+		PushState{"/list", "List"},
+	}}
+
+	expected := `function (doc) {
+  doc = doc || document;
+  var n0 = [doc];
+  var n1 = first(n0, "#list");
+  n1.forEach(function (e) { e.insertAdjacentHTML("beforeend", "\u003cli\u003enew\u003c/li\u003e"); });
+  n1.forEach(function (e) { e.classList.add("loaded"); });
+  setTimeout(function () {
+    var n2 = all(n1, "li.new");
+    n2.forEach(function (e) { e.classList.remove("new"); });
+  }, 1000);
+  history.pushState(null, "List", "/list");
+
+  function all(nodes, selector) {
+    var result = [];
+    nodes.forEach(function (n) {
+      if (n.querySelectorAll) result.push.apply(result, n.querySelectorAll(selector));
+    });
+    return result;
+  }
+
+  function first(nodes, selector) {
+    var result = [];
+    nodes.forEach(function (n) {
+      var match = n.querySelector && n.querySelector(selector);
+      if (match) result.push(match);
+    });
+    return result;
+  }
+}`
+
+	if result := CompileJS(delta); result != expected {
+		t.Error("Expected ", expected, ", got", result)
+	}
+}
+
+func TestCompileJSUnsupported(t *testing.T) {
+	result := CompileJS(List{[]Delta{
+		SetAttr{map[string]string{"id": "x"}},
+		First{S("body"), List{[]Delta{
+			chartUpdate{"sales"},
+			Range{"items", List{[]Delta{Focus{}, HTML{HTMLFromString("</script>")}}}},
+		}}},
+	}})
+
+	for _, line := range []string{
+		"  // SetAttr has no effect on the document\n",
+		"  // Op(65537) is not supported\n",
+		"  // Focus has no effect on ranges\n",
+		"    rangeClear(e);\n",
+		"  function rangeEnd(start) {\n",
+	} {
+		if !strings.Contains(result, line) {
+			t.Error("Expected ", line, " in", result)
+		}
+	}
+
+	if strings.Contains(result, "</script>") {
+		t.Error("Expected compiled code to be safe to inline in a script element, got", result)
+	}
+}
+
+func TestCompileJSDocument(t *testing.T) {
+	result := CompileJS(List{[]Delta{
+		DispatchEvent{"wit:ping", nil, false},
+		ScrollTo{10, 20},
+		Focus{},
+		Blur{},
+	}})
+
+	for _, line := range []string{
+		"  n0.forEach(function (e) { e.dispatchEvent(new CustomEvent(\"wit:ping\", { detail: null, bubbles: false })); });\n",
+		"  n0.forEach(function (e) { (e.defaultView || window).scrollTo(20, 10); });\n",
+		"  n0.forEach(function (e) { (e.defaultView || window).focus(); });\n",
+		"  n0.forEach(function (e) { if (e.activeElement) e.activeElement.blur(); });\n",
+	} {
+		if !strings.Contains(result, line) {
+			t.Error("Expected ", line, " in", result)
+		}
+	}
+
+	if strings.Contains(result, "no effect") {
+		t.Error("Expected effects to be compiled on the document, got", result)
+	}
+}
+
+func TestCompileJSImportant(t *testing.T) {
+	result := CompileJS(First{S("p"), SetStyles{map[string]string{
+		"color":  "red !important",
+		"margin": "0 ! IMPORTANT ",
+		"width":  "important",
+	}}})
+
+	for _, line := range []string{
+		`e.style.setProperty("color", "red", "important");`,
+		`e.style.setProperty("margin", "0", "important");`,
+		`e.style.setProperty("width", "important");`,
+	} {
+		if !strings.Contains(result, line) {
+			t.Error("Expected ", line, " in", result)
+		}
+	}
+}
+
+func TestCompileJSLoad(t *testing.T) {
+	result := CompileJS(List{[]Delta{
+		LoadScript{"/chart.js", map[string]string{"defer": ""}},
+		First{S("#chart"), AddClasses{"ready"}},
+	}})
+
+	expected := `  load("script[src=\"/chart.js\"]", "script", { "defer": "", "src": "/chart.js" }, function () {
+    var n1 = first(n0, "#chart");
+    n1.forEach(function (e) { e.classList.add("ready"); });
+  });
+`
+
+	if !strings.Contains(result, expected) {
+		t.Error("Expected ", expected, " in", result)
+	}
+
+	result = CompileJS(List{[]Delta{
+		First{S("head"), LoadStylesheet{"/chart.css", nil}},
+		Delay{time.Second, List{[]Delta{
+			LoadScript{"/chart.js", nil},
+			First{S("#chart"), AddClasses{"ready"}},
+		}}},
+		PushState{"/chart", "Chart"},
+		LoadScript{"/stats.js", nil},
+	}})
+
+	expected = `  load("link[href=\"/chart.css\"]", "link", { "href": "/chart.css", "rel": "stylesheet" }, function () {
+    setTimeout(function () {
+      load("script[src=\"/chart.js\"]", "script", { "src": "/chart.js" }, function () {
+        var n2 = first(n0, "#chart");
+        n2.forEach(function (e) { e.classList.add("ready"); });
+      });
+    }, 1000);
+    history.pushState(null, "Chart", "/chart");
+    load("script[src=\"/stats.js\"]", "script", { "src": "/stats.js" });
+  });
+`
+
+	if !strings.Contains(result, expected) {
+		t.Error("Expected ", expected, " in", result)
+	}
+}
+
+func TestCompileJSRegExp(t *testing.T) {
+	for _, test := range []struct {
+		find, replace, expected string
+	}{
+		{`(h)(ello)`, "${1}o${2}", `new RegExp("(h)(ello)", "gu"), "$01o$02"`},
+		{`(?i)(?P<word>k)\.`, "$word-$0$$$9", `new RegExp("([Kk\\u{212a}])\\u{2e}", "gu"), "$01-$\u0026$$"`},
+		{`(?m)^a$`, "b", `new RegExp("(?\u003c=^|\\n)a(?=\\n|$)", "gu"), "b"`},
+	} {
+		result := CompileJS(First{S("p"), ReplaceText{test.find, test.replace, true}})
+		if !strings.Contains(result, "replaceText(e, "+test.expected+");") {
+			t.Error("Expected ", test.expected, " in", result)
+		}
+
+		if !strings.Contains(result, "  function isRawText(e) {\n") {
+			t.Error("Expected raw text elements to be skipped, got", result)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package wit
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Decoder reads a stream of JSON-encoded deltas, either concatenated or
+// separated by whitespace such as newlines
+type Decoder struct {
+	dec  *json.Decoder
+	opts decodeOptions
+}
+
+// NewDecoder returns a new decoder that reads from r
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{json.NewDecoder(r), decodeOptions{false, ProtocolVersion}}
+}
+
+// Strict makes the decoder return a *DecodeError for malformed deltas
+// instead of skipping them, see UnmarshalStrict
+func (d *Decoder) Strict() {
+	d.opts.strict = true
+}
+
+// Version sets the protocol version spoken by the peer. Deltas introduced
+// after it are rejected with a *DecodeError, even if the decoder isn't strict.
+func (d *Decoder) Version(version int) {
+	d.opts.version = version
+}
+
+// Decode reads the next delta from the stream, returning as soon as it's
+// complete. Unless the decoder is strict, deltas which can't be decoded are
+// skipped. io.EOF is returned once the stream ends.
+func (d *Decoder) Decode() (Delta, error) {
+	for {
+		var payload json.RawMessage
+		if err := d.dec.Decode(&payload); err != nil {
+			return nil, err
+		}
+
+		input, err := unmarshalJSONInput(payload)
+		if err != nil {
+			return nil, err
+		}
+
+		delta, err := decodeJSONDelta(input, "$", d.opts)
+		if err != nil || delta != nil {
+			return delta, err
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package wit
+
+import (
+	"io"
+	"testing"
+)
+
+func TestDecoder(t *testing.T) {
+	r, w := io.Pipe()
+	decoder := NewDecoder(r)
+
+	go func() {
+		w.Write([]byte(expectedJSON + "\n"))
+		w.Write([]byte(`[999][10]`))
+	}()
+
+	result, err := decoder.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resultJSON, _ := result.MarshalJSON()
+	if string(resultJSON) != expectedJSON {
+		t.Error("Expected ", expectedJSON, ", got", string(resultJSON))
+	}
+
+	result, err = decoder.Decode()
+	if _, ok := result.(Remove); !ok || err != nil {
+		t.Error("Expected Remove, got", result, err)
+	}
+
+	w.Close()
+	if _, err = decoder.Decode(); err != io.EOF {
+		t.Error("Expected EOF, got", err)
+	}
+}
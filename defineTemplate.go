@@ -0,0 +1,38 @@
+package wit
+
+import "strconv"
+
+// DefineTemplate registers the provided HTML under the given name, so that
+// it can be later instantiated using UseTemplate. Placeholders of the form
+// {{param}} found in text and attribute values are filled on instantiation.
+type DefineTemplate struct {
+	Name string
+	HTMLSource
+}
+
+// Apply applies the delta to the provided elements
+func (t DefineTemplate) Apply(d Document) {
+	if d.templates != nil {
+		d.templates[t.Name] = t.HTMLSource
+	}
+}
+
+// MarshalJSON marshals the delta to JSON format
+func (t DefineTemplate) MarshalJSON() ([]byte, error) {
+	return []byte("[" + defineTemplateLabelJSON + "," + strconv.Quote(t.Name) + "," + strconv.Quote(t.HTMLSource.String()) + "]"), nil
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (t DefineTemplate) MarshalBinary() ([]byte, error) {
+	return marshalBinary(t)
+}
+
+// UnmarshalBinary sets *t to the delta encoded in the binary format
+func (t *DefineTemplate) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, t)
+}
+
+// Op returns the operation performed by the delta
+func (t DefineTemplate) Op() Op {
+	return OpDefineTemplate
+}
@@ -0,0 +1,75 @@
+package wit
+
+import (
+	"bytes"
+	"strconv"
+	"time"
+)
+
+// Clock schedules functions to be run in the future
+type Clock interface {
+	AfterFunc(duration time.Duration, f func())
+}
+
+type systemClock struct{}
+
+func (s systemClock) AfterFunc(duration time.Duration, f func()) {
+	time.AfterFunc(duration, f)
+}
+
+// SystemClock schedules functions using the time package. Delayed deltas
+// will run on their own goroutine, so access to the document must be
+// synchronized by the caller.
+var SystemClock Clock = systemClock{}
+
+// Delay applies given delta after the provided duration, which is sent
+// to clients with millisecond precision
+type Delay struct {
+	Duration time.Duration
+	Delta
+}
+
+// Apply applies the delta to the provided elements
+func (dl Delay) Apply(d Document) {
+	if d.clock == nil {
+		dl.Delta.Apply(d)
+		return
+	}
+
+	d.clock.AfterFunc(dl.Duration, func() {
+		dl.Delta.Apply(d)
+	})
+}
+
+// MarshalJSON marshals the delta to JSON format
+func (dl Delay) MarshalJSON() ([]byte, error) {
+	return marshalJSON(dl)
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (dl Delay) MarshalBinary() ([]byte, error) {
+	return marshalBinary(dl)
+}
+
+// UnmarshalBinary sets *dl to the delta encoded in the binary format
+func (dl *Delay) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, dl)
+}
+
+// Op returns the operation performed by the delta
+func (dl Delay) Op() Op {
+	return OpDelay
+}
+
+func (dl Delay) writeJSON(b *bytes.Buffer) error {
+	writeJSONOpen(b, delayLabelJSON)
+	b.WriteByte(',')
+	b.WriteString(strconv.FormatInt(dl.Duration.Milliseconds(), 10))
+
+	if err := writeJSONDeltaParameter(b, dl.Delta); err != nil {
+		return err
+	}
+
+	b.WriteByte(']')
+	return nil
+}
@@ -0,0 +1,62 @@
+package wit
+
+import (
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	scheduled []func()
+	durations []time.Duration
+}
+
+func (f *fakeClock) AfterFunc(duration time.Duration, fn func()) {
+	f.durations = append(f.durations, duration)
+	f.scheduled = append(f.scheduled, fn)
+}
+
+var fadeOut = First{Body, First{S(".toast"), List{[]Delta{
+	SetAttr{map[string]string{"data-leaving": ""}},
+	Delay{300 * time.Millisecond, Remove{}},
+}}}}
+
+func TestDelayImmediate(t *testing.T) {
+	doc := NewDocument()
+	First{Body, HTML{HTMLFromString(`<div class="toast"></div>`)}}.Apply(doc)
+	fadeOut.Apply(doc)
+
+	expected := `<!DOCTYPE html><html><head></head><body></body></html>`
+	if result := render(doc); result != expected {
+		t.Error("Expected ", expected, ", got", result)
+	}
+}
+
+func TestDelayScheduled(t *testing.T) {
+	clock := &fakeClock{}
+	doc := NewDocument().WithClock(clock)
+	First{Body, HTML{HTMLFromString(`<div class="toast"></div>`)}}.Apply(doc)
+	fadeOut.Apply(doc)
+
+	expected := `<!DOCTYPE html><html><head></head><body><div class="toast" data-leaving=""></div></body></html>`
+	if result := render(doc); result != expected {
+		t.Error("Expected ", expected, ", got", result)
+	}
+
+	if len(clock.scheduled) != 1 || clock.durations[0] != 300*time.Millisecond {
+		t.Fatal("Expected one delta scheduled after 300ms, got", clock.durations)
+	}
+
+	clock.scheduled[0]()
+
+	expected = `<!DOCTYPE html><html><head></head><body></body></html>`
+	if result := render(doc); result != expected {
+		t.Error("Expected ", expected, ", got", result)
+	}
+}
+
+func TestDelayJSON(t *testing.T) {
+	expected := `[1,[3,"body",[3,".toast",[18,{"data-leaving":""}],[34,300,[10]]]]]`
+	if result := assertJSONRoundTrip(t, List{[]Delta{fadeOut}}); result != expected {
+		t.Error("Expected ", expected, ", got", result)
+	}
+}
@@ -1,7 +1,49 @@
 package wit
 
+import (
+	"bytes"
+	"encoding/json"
+)
+
 // Delta represents a page change
 type Delta interface {
 	Apply(document Document)
 	MarshalJSON() ([]byte, error)
 }
+
+// OpDelta is implemented by deltas which report the operation they perform,
+// as all of the deltas in this package do. Custom deltas may implement it to
+// avoid being marshalled by OpOf.
+type OpDelta interface {
+	Delta
+	Op() Op
+}
+
+// OpOf returns the operation performed by the provided delta. The op of
+// deltas not implementing OpDelta is read from the label of their JSON
+// encoding, and is 0 if it can't be read.
+func OpOf(delta Delta) Op {
+	if d, ok := delta.(OpDelta); ok {
+		return d.Op()
+	}
+
+	deltaJSON, err := delta.MarshalJSON()
+	if err != nil {
+		return 0
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(deltaJSON))
+	decoder.UseNumber()
+
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return 0
+	}
+
+	token, err := decoder.Token()
+	if err != nil {
+		return 0
+	}
+
+	label, _ := labelOf(token)
+	return label
+}
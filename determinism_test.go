@@ -0,0 +1,51 @@
+package wit
+
+import "testing"
+
+func TestDeterministicJSON(t *testing.T) {
+	unordered := List{[]Delta{
+		SetAttr{map[string]string{"id": "main", "class": "a", "data-x": "1", "title": "t"}},
+		SetStyles{map[string]string{"color": "red", "margin": "0", "padding": "1px"}},
+		UseTemplate{"row", map[string]string{"name": "n", "id": "1", "age": "2"}},
+	}}
+
+	expected := `[1,[18,{"class":"a","data-x":"1","id":"main","title":"t"}],[21,{"color":"red","margin":"0","padding":"1px"}],[43,"row",{"age":"2","id":"1","name":"n"}]]`
+
+	for i := 0; i < 20; i++ {
+		result, err := unordered.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(result) != expected {
+			t.Fatal("Expected ", expected, ", got", string(result))
+		}
+	}
+}
+
+func TestDeterministicApply(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		doc := NewDocument()
+		First{Body, HTML{HTMLFromString(`<div class="b a" style="color: red; margin: 0" rel="x y z"></div><p></p>`)}}.Apply(doc)
+
+		List{[]Delta{
+			First{S("div"), List{[]Delta{
+				AddClasses{"c a d"},
+				RmClasses{"b"},
+				SetStyles{map[string]string{"padding": "1px", "color": "blue", "border": "0"}},
+				RmStyles{[]string{"margin"}},
+				RmTokens{"rel", "y"},
+				SetAttr{map[string]string{"data-z": "1", "data-a": "2"}},
+			}}},
+			First{S("p"), List{[]Delta{
+				ReplaceAttr{map[string]string{"title": "t", "id": "p", "lang": "en"}},
+				SetStyles{map[string]string{"top": "0", "left": "0"}},
+			}}},
+		}}.Apply(doc)
+
+		expected := `<!DOCTYPE html><html><head></head><body><div class="a c d" style="color: blue;border: 0;padding: 1px;" rel="x z" data-a="2" data-z="1"></div><p id="p" lang="en" title="t" style="left: 0;top: 0;"></p></body></html>`
+		if result := render(doc); result != expected {
+			t.Fatal("Expected ", expected, ", got", result)
+		}
+	}
+}
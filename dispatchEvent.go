@@ -0,0 +1,47 @@
+package wit
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// DispatchEvent dispatches a custom event on matching elements, with the
+// provided JSON-encoded detail. It only has effect on the client.
+type DispatchEvent struct {
+	Type    string
+	Detail  json.RawMessage
+	Bubbles bool
+}
+
+// Apply applies the delta to the provided elements
+func (e DispatchEvent) Apply(d Document) {}
+
+// MarshalJSON marshals the delta to JSON format
+func (e DispatchEvent) MarshalJSON() ([]byte, error) {
+	detail := "null"
+	if len(e.Detail) != 0 {
+		compacted, err := compactJSON(e.Detail)
+		if err != nil {
+			return nil, err
+		}
+
+		detail = compacted
+	}
+
+	return []byte("[" + dispatchEventLabelJSON + "," + strconv.Quote(e.Type) + "," + detail + "," + strconv.FormatBool(e.Bubbles) + "]"), nil
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (e DispatchEvent) MarshalBinary() ([]byte, error) {
+	return marshalBinary(e)
+}
+
+// UnmarshalBinary sets *e to the delta encoded in the binary format
+func (e *DispatchEvent) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, e)
+}
+
+// Op returns the operation performed by the delta
+func (e DispatchEvent) Op() Op {
+	return OpDispatchEvent
+}
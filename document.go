@@ -10,6 +10,9 @@ import (
 type Document struct {
 	root  *html.Node
 	nodes []*html.Node
+	clock Clock
+
+	templates map[string]HTMLSource
 }
 
 var baseNode, _ = html.Parse(strings.NewReader("<!DOCTYPE html><html><head></head><body></body></html>"))
@@ -17,11 +20,21 @@ var baseNode, _ = html.Parse(strings.NewReader("<!DOCTYPE html><html><head></hea
 func NewDocument() Document {
 	root := cloneNode(baseNode, map[*html.Node]*html.Node{})
 	return Document{
-		root,
-		[]*html.Node{root},
+		root:  root,
+		nodes: []*html.Node{root},
+
+		templates: map[string]HTMLSource{},
 	}
 }
 
+// WithClock returns a copy of the document which schedules delayed deltas
+// using the provided clock. Without a clock, delayed deltas are applied
+// immediately.
+func (d Document) WithClock(clock Clock) Document {
+	d.clock = clock
+	return d
+}
+
 func (d Document) Render(w io.Writer) {
 	html.Render(w, d.root)
 }
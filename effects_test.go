@@ -0,0 +1,55 @@
+package wit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClientEffects(t *testing.T) {
+	effects := List{[]Delta{
+		First{S("input:invalid"), List{[]Delta{
+			Focus{},
+			ScrollIntoView{map[string]string{"block": "center"}},
+			SelectText{0, 5},
+		}}},
+		First{S(".active"), Blur{}},
+		First{Body, ScrollTo{100, 0}},
+		All{S("chart-widget"), DispatchEvent{"wit:update", []byte(`{"points": [1, 2]}`), true}},
+		DispatchEvent{"wit:ping", nil, false},
+		PushState{"/cart?step=2", "Cart"},
+		ReplaceState{"/cart", ""},
+		Redirect{"/login"},
+		Reload{},
+	}}
+
+	expected := `[1,[3,"input:invalid",[28],[30,{"block":"center"}],[32,0,5]],[3,".active",[29]],[3,"body",[31,100,0]],[4,"chart-widget",[33,"wit:update",{"points":[1,2]},true]],[33,"wit:ping",null,false],[38,"/cart?step=2","Cart"],[39,"/cart",""],[40,"/login"],[41]]`
+	if result := assertJSONRoundTrip(t, effects); result != expected {
+		t.Error("Expected ", expected, ", got", result)
+	}
+}
+
+func TestDispatchEventDetail(t *testing.T) {
+	event := List{[]Delta{
+		First{S("chart-widget"), DispatchEvent{"wit:update", []byte(`{"z":1,"id":9007199254740993,"a":[0.1]}`), true}},
+		DispatchEvent{"wit:ping", []byte(`12345678901234567890`), false},
+	}}
+
+	expected := `[1,[3,"chart-widget",[33,"wit:update",{"z":1,"id":9007199254740993,"a":[0.1]},true]],[33,"wit:ping",12345678901234567890,false]]`
+	if result := assertJSONRoundTrip(t, event); result != expected {
+		t.Error("Expected ", expected, ", got", result)
+	}
+
+	for _, decode := range []func() (Delta, error){
+		func() (Delta, error) { return UnmarshalStrict([]byte(expected)) },
+		func() (Delta, error) { return NewDecoder(strings.NewReader(expected)).Decode() },
+	} {
+		result, err := decode()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resultJSON, _ := result.MarshalJSON(); string(resultJSON) != expected {
+			t.Error("Expected ", expected, ", got", string(resultJSON))
+		}
+	}
+}
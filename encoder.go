@@ -0,0 +1,129 @@
+package wit
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"sync"
+)
+
+// Buffers bigger than this are not returned to the pool, so that a single
+// huge delta doesn't pin its memory forever
+const maxPooledBufferSize = 1 << 20
+
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+func getBuffer() *bytes.Buffer {
+	b := bufferPool.Get().(*bytes.Buffer)
+	b.Reset()
+	return b
+}
+
+func putBuffer(b *bytes.Buffer) {
+	if b.Cap() <= maxPooledBufferSize {
+		bufferPool.Put(b)
+	}
+}
+
+// jsonWriter is implemented by deltas which can write their JSON encoding
+// directly into a buffer, instead of building intermediate slices
+type jsonWriter interface {
+	writeJSON(b *bytes.Buffer) error
+}
+
+func marshalJSON(delta jsonWriter) ([]byte, error) {
+	b := getBuffer()
+	defer putBuffer(b)
+
+	if err := delta.writeJSON(b); err != nil {
+		return nil, err
+	}
+
+	return append([]byte(nil), b.Bytes()...), nil
+}
+
+func writeJSONDelta(b *bytes.Buffer, delta Delta) error {
+	if writer, ok := delta.(jsonWriter); ok {
+		return writer.writeJSON(b)
+	}
+
+	deltaJSON, err := delta.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	b.Write(deltaJSON)
+	return nil
+}
+
+func writeJSONDeltas(b *bytes.Buffer, deltas []Delta) error {
+	for _, delta := range deltas {
+		b.WriteByte(',')
+		if err := writeJSONDelta(b, delta); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeJSONDeltaParameter writes the provided delta as the trailing
+// parameters of its parent, flattening lists
+func writeJSONDeltaParameter(b *bytes.Buffer, delta Delta) error {
+	if list, ok := delta.(List); ok {
+		return writeJSONDeltas(b, list.Deltas)
+	}
+
+	b.WriteByte(',')
+	return writeJSONDelta(b, delta)
+}
+
+func writeJSONOpen(b *bytes.Buffer, label string) {
+	b.WriteByte('[')
+	b.WriteString(label)
+}
+
+func writeJSONString(b *bytes.Buffer, str string) {
+	b.WriteByte(',')
+	b.WriteString(strconv.Quote(str))
+}
+
+// Encoder writes the JSON encoding of deltas to an output stream
+type Encoder struct {
+	w       io.Writer
+	version int
+}
+
+// NewEncoder returns a new encoder that writes to w
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w, ProtocolVersion}
+}
+
+// Version sets the protocol version spoken by the peer, as returned by
+// NegotiateVersion. Deltas introduced after it are rejected with a
+// *DecodeError instead of being written.
+func (e *Encoder) Version(version int) {
+	e.version = version
+}
+
+// Encode writes the JSON encoding of the delta to the stream, byte-identical
+// to the output of its MarshalJSON method
+func (e *Encoder) Encode(delta Delta) error {
+	if err := checkVersion(delta, e.version); err != nil {
+		return err
+	}
+
+	b := getBuffer()
+	defer putBuffer(b)
+
+	if err := writeJSONDelta(b, delta); err != nil {
+		return err
+	}
+
+	_, err := e.w.Write(b.Bytes())
+	return err
+}
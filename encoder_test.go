@@ -0,0 +1,35 @@
+package wit
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+func TestEncoder(t *testing.T) {
+	var b bytes.Buffer
+	encoder := NewEncoder(&b)
+
+	encoder.Encode(delta)
+	encoder.Encode(Remove{})
+
+	expected := expectedJSON + `[10]`
+	if b.String() != expected {
+		t.Error("Expected ", expected, ", got", b.String())
+	}
+}
+
+func BenchmarkEncoder(b *testing.B) {
+	deltas := make([]Delta, 1000)
+	for i := range deltas {
+		deltas[i] = delta
+	}
+
+	big := List{deltas}
+	encoder := NewEncoder(ioutil.Discard)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		encoder.Encode(big)
+	}
+}
@@ -1,7 +1,7 @@
 package wit
 
 import (
-	"strconv"
+	"bytes"
 
 	"github.com/andybalholm/cascadia"
 	"golang.org/x/net/html"
@@ -30,5 +30,32 @@ func (f First) Apply(d Document) {
 
 // MarshalJSON marshals the delta to JSON format
 func (f First) MarshalJSON() ([]byte, error) {
-	return []byte("[" + selectorLabelJSON + "," + strconv.Quote(f.Selector.String()) + deltaToCSV(f.Delta) + "]"), nil
+	return marshalJSON(f)
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (f First) MarshalBinary() ([]byte, error) {
+	return marshalBinary(f)
+}
+
+// UnmarshalBinary sets *f to the delta encoded in the binary format
+func (f *First) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, f)
+}
+
+// Op returns the operation performed by the delta
+func (f First) Op() Op {
+	return OpFirst
+}
+
+func (f First) writeJSON(b *bytes.Buffer) error {
+	writeJSONOpen(b, selectorLabelJSON)
+	writeJSONString(b, f.Selector.String())
+
+	if err := writeJSONDeltaParameter(b, f.Delta); err != nil {
+		return err
+	}
+
+	b.WriteByte(']')
+	return nil
 }
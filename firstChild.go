@@ -1,6 +1,8 @@
 package wit
 
 import (
+	"bytes"
+
 	"golang.org/x/net/html"
 )
 
@@ -30,5 +32,31 @@ func (fc FirstChild) Apply(d Document) {
 
 // MarshalJSON marshals the delta to JSON format
 func (fc FirstChild) MarshalJSON() ([]byte, error) {
-	return []byte("[" + firstChildLabelJSON + deltaToCSV(fc.Delta) + "]"), nil
+	return marshalJSON(fc)
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (fc FirstChild) MarshalBinary() ([]byte, error) {
+	return marshalBinary(fc)
+}
+
+// UnmarshalBinary sets *fc to the delta encoded in the binary format
+func (fc *FirstChild) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, fc)
+}
+
+// Op returns the operation performed by the delta
+func (fc FirstChild) Op() Op {
+	return OpFirstChild
+}
+
+func (fc FirstChild) writeJSON(b *bytes.Buffer) error {
+	writeJSONOpen(b, firstChildLabelJSON)
+
+	if err := writeJSONDeltaParameter(b, fc.Delta); err != nil {
+		return err
+	}
+
+	b.WriteByte(']')
+	return nil
 }
@@ -0,0 +1,27 @@
+package wit
+
+// Focus focuses the first matching element. It only has effect on the client.
+type Focus struct{}
+
+// Apply applies the delta to the provided elements
+func (f Focus) Apply(d Document) {}
+
+// MarshalJSON marshals the delta to JSON format
+func (f Focus) MarshalJSON() ([]byte, error) {
+	return []byte("[" + focusLabelJSON + "]"), nil
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (f Focus) MarshalBinary() ([]byte, error) {
+	return marshalBinary(f)
+}
+
+// UnmarshalBinary sets *f to the delta encoded in the binary format
+func (f *Focus) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, f)
+}
+
+// Op returns the operation performed by the delta
+func (f Focus) Op() Op {
+	return OpFocus
+}
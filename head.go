@@ -0,0 +1,61 @@
+package wit
+
+import (
+	"html"
+	"sort"
+)
+
+// SetTitle sets the title of the document, replacing any existing one
+func SetTitle(title string) Delta {
+	return First{Head, List{[]Delta{
+		All{S("title"), Remove{}},
+		Prepend{HTMLFromString("<title>" + html.EscapeString(title) + "</title>")},
+	}}}
+}
+
+// SetMetaName upserts the <meta> element with the provided name
+func SetMetaName(name, content string) Delta {
+	return upsertHead("meta", "name", name, map[string]string{"content": content})
+}
+
+// SetMetaProperty upserts the <meta> element with the provided property,
+// as used by OpenGraph tags
+func SetMetaProperty(property, content string) Delta {
+	return upsertHead("meta", "property", property, map[string]string{"content": content})
+}
+
+// SetLink upserts the <link> element with the provided rel
+func SetLink(rel, href string, attributes map[string]string) Delta {
+	attr := map[string]string{}
+	for key, value := range attributes {
+		attr[key] = value
+	}
+
+	attr["href"] = href
+	return upsertHead("link", "rel", rel, attr)
+}
+
+func upsertHead(tag, keyAttr, key string, attributes map[string]string) Delta {
+	return First{Head, List{[]Delta{
+		All{S(tag + "[" + keyAttr + "=" + quoteCSSString(key) + "]"), Remove{}},
+		Append{HTMLFromString(buildTag(tag, keyAttr, key, attributes))},
+	}}}
+}
+
+func buildTag(tag, keyAttr, key string, attributes map[string]string) string {
+	keys := make([]string, 0, len(attributes))
+	for attrKey := range attributes {
+		if attrKey != keyAttr {
+			keys = append(keys, attrKey)
+		}
+	}
+
+	sort.Strings(keys)
+
+	result := "<" + tag + " " + keyAttr + "=\"" + html.EscapeString(key) + "\""
+	for _, attrKey := range keys {
+		result += " " + attrKey + "=\"" + html.EscapeString(attributes[attrKey]) + "\""
+	}
+
+	return result + ">"
+}
@@ -0,0 +1,49 @@
+package wit
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestHeadUpserts(t *testing.T) {
+	var b bytes.Buffer
+	doc := NewDocument()
+
+	List{[]Delta{
+		SetTitle("First"),
+		SetMetaProperty("og:title", "First"),
+		SetLink("canonical", "/first", nil),
+		SetTitle("Second & last"),
+		SetMetaProperty("og:title", "Second \"quoted\""),
+		SetMetaName("description", "Page"),
+		SetLink("canonical", "/second", map[string]string{"hreflang": "en"}),
+	}}.Apply(doc)
+
+	doc.Render(&b)
+
+	expected := `<!DOCTYPE html><html><head><title>Second &amp; last</title><meta property="og:title" content="Second &#34;quoted&#34;"/><meta name="description" content="Page"/><link rel="canonical" href="/second" hreflang="en"/></head><body></body></html>`
+	if b.String() != expected {
+		t.Error("Expected ", expected, ", got", b.String())
+	}
+}
+
+func TestLoadAssets(t *testing.T) {
+	var b bytes.Buffer
+	doc := NewDocument()
+
+	load := List{[]Delta{
+		LoadScript{"/chart.js", map[string]string{"defer": ""}},
+		LoadStylesheet{"/chart.css", nil},
+	}}
+
+	load.Apply(doc)
+	load.Apply(doc)
+	doc.Render(&b)
+
+	expected := `<!DOCTYPE html><html><head><script src="/chart.js" defer=""></script><link href="/chart.css" rel="stylesheet"/></head><body></body></html>`
+	if b.String() != expected {
+		t.Error("Expected ", expected, ", got", b.String())
+	}
+
+	assertJSONRoundTrip(t, load)
+}
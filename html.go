@@ -12,6 +12,12 @@ type HTML struct {
 // Apply applies the delta to the provided elements
 func (h HTML) Apply(d Document) {
 	for _, node := range d.nodes {
+		if isRangeStart(node) {
+			clearRange(node)
+			insertInRange(node, h.HTMLSource.Nodes(node.Parent), true)
+			continue
+		}
+
 		for node.FirstChild != nil {
 			node.RemoveChild(node.FirstChild)
 		}
@@ -27,3 +33,18 @@ func (h HTML) Apply(d Document) {
 func (h HTML) MarshalJSON() ([]byte, error) {
 	return []byte("[" + htmlLabelJSON + "," + strconv.Quote(h.HTMLSource.String()) + "]"), nil
 }
+
+// MarshalBinary marshals the delta to the binary format
+func (h HTML) MarshalBinary() ([]byte, error) {
+	return marshalBinary(h)
+}
+
+// UnmarshalBinary sets *h to the delta encoded in the binary format
+func (h *HTML) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, h)
+}
+
+// Op returns the operation performed by the delta
+func (h HTML) Op() Op {
+	return OpHTML
+}
@@ -36,3 +36,18 @@ func (i InsertAfter) Apply(d Document) {
 func (i InsertAfter) MarshalJSON() ([]byte, error) {
 	return []byte("[" + insertAfterLabelJSON + "," + strconv.Quote(i.HTMLSource.String()) + "]"), nil
 }
+
+// MarshalBinary marshals the delta to the binary format
+func (i InsertAfter) MarshalBinary() ([]byte, error) {
+	return marshalBinary(i)
+}
+
+// UnmarshalBinary sets *i to the delta encoded in the binary format
+func (i *InsertAfter) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, i)
+}
+
+// Op returns the operation performed by the delta
+func (i InsertAfter) Op() Op {
+	return OpInsertAfter
+}
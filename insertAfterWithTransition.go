@@ -0,0 +1,40 @@
+package wit
+
+import (
+	"strconv"
+	"time"
+)
+
+// InsertAfterWithTransition inserts the provided HTML after matching elements
+// with the provided enter classes, which are removed once their transition
+// ends or the timeout expires. The server-side document never holds the classes.
+type InsertAfterWithTransition struct {
+	HTMLSource
+	Classes string
+	Timeout time.Duration
+}
+
+// Apply applies the delta to the provided elements
+func (i InsertAfterWithTransition) Apply(d Document) {
+	InsertAfter{i.HTMLSource}.Apply(d)
+}
+
+// MarshalJSON marshals the delta to JSON format
+func (i InsertAfterWithTransition) MarshalJSON() ([]byte, error) {
+	return []byte("[" + insertAfterWithTransitionLabelJSON + "," + strconv.Quote(i.HTMLSource.String()) + "," + strconv.Quote(i.Classes) + "," + strconv.FormatInt(i.Timeout.Milliseconds(), 10) + "]"), nil
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (i InsertAfterWithTransition) MarshalBinary() ([]byte, error) {
+	return marshalBinary(i)
+}
+
+// UnmarshalBinary sets *i to the delta encoded in the binary format
+func (i *InsertAfterWithTransition) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, i)
+}
+
+// Op returns the operation performed by the delta
+func (i InsertAfterWithTransition) Op() Op {
+	return OpInsertAfterWithTransition
+}
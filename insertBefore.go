@@ -29,3 +29,18 @@ func (i InsertBefore) Apply(d Document) {
 func (i InsertBefore) MarshalJSON() ([]byte, error) {
 	return []byte("[" + insertBeforeLabelJSON + "," + strconv.Quote(i.HTMLSource.String()) + "]"), nil
 }
+
+// MarshalBinary marshals the delta to the binary format
+func (i InsertBefore) MarshalBinary() ([]byte, error) {
+	return marshalBinary(i)
+}
+
+// UnmarshalBinary sets *i to the delta encoded in the binary format
+func (i *InsertBefore) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, i)
+}
+
+// Op returns the operation performed by the delta
+func (i InsertBefore) Op() Op {
+	return OpInsertBefore
+}
@@ -0,0 +1,297 @@
+package wit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// Interning streams keep a per-connection dictionary of the selectors and
+// HTML strings seen so far, in order of appearance. Both ends add every such
+// string to the dictionary the first time they see it, and later occurrences
+// may be sent as the numeric id of its entry instead. Strings whose id would
+// not be shorter are always sent in full, and not added again. The number of
+// entries, their length and their total size are capped so peers can't make
+// dictionaries grow without bounds.
+
+const (
+	maxInternedStrings      = 1 << 16
+	maxInternedStringLength = 1 << 12
+	maxInternedBytes        = 1 << 20
+)
+
+// internable reports whether the string fits in a dictionary holding count
+// strings of the given total size
+func internable(str string, count, size int) bool {
+	return count < maxInternedStrings && len(str) <= maxInternedStringLength && size+len(str) <= maxInternedBytes
+}
+
+func labelOf(value interface{}) (Op, bool) {
+	switch v := value.(type) {
+	case float64:
+		return Op(v), v == float64(int(v))
+	case json.Number:
+		label, err := strconv.Atoi(string(v))
+		return Op(label), err == nil
+	}
+
+	return 0, false
+}
+
+// walkInterned calls f for every selector and HTML argument of the provided
+// delta and its children, in order, replacing the argument by its result
+func walkInterned(input []interface{}, path string, f func(value interface{}, path string) (interface{}, error)) error {
+	if len(input) == 0 {
+		return nil
+	}
+
+	label, ok := labelOf(input[0])
+	if !ok {
+		return nil
+	}
+
+	spec, ok := labels[label]
+	if !ok {
+		return nil
+	}
+
+	for i := 1; i < len(input); i++ {
+		arg, ok := spec.arg(i)
+		if !ok {
+			break
+		}
+
+		argPath := path + "[" + strconv.Itoa(i) + "]"
+
+		switch arg.kind {
+		case selectorArg, htmlArg, nullableHTMLArg:
+			value, err := f(input[i], argPath)
+			if err != nil {
+				return err
+			}
+
+			input[i] = value
+
+		case deltaArg:
+			if child, ok := input[i].([]interface{}); ok {
+				if err := walkInterned(child, argPath, f); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func writeJSONValue(b *bytes.Buffer, value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		b.WriteString("null")
+
+	case bool:
+		b.WriteString(strconv.FormatBool(v))
+
+	case json.Number:
+		b.WriteString(string(v))
+
+	case string:
+		b.WriteString(strconv.Quote(v))
+
+	case []interface{}:
+		b.WriteByte('[')
+		for i, item := range v {
+			if i != 0 {
+				b.WriteByte(',')
+			}
+
+			writeJSONValue(b, item)
+		}
+
+		b.WriteByte(']')
+
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		b.WriteByte('{')
+		for i, key := range keys {
+			if i != 0 {
+				b.WriteByte(',')
+			}
+
+			b.WriteString(strconv.Quote(key))
+			b.WriteByte(':')
+			writeJSONValue(b, v[key])
+		}
+
+		b.WriteByte('}')
+	}
+}
+
+// InterningEncoder writes JSON-encoded deltas to an output stream, replacing
+// repeated selectors and HTML strings by references to their first occurrence.
+// Its output must be read using an InterningDecoder.
+type InterningEncoder struct {
+	w       io.Writer
+	version int
+	ids     map[string]int
+	size    int
+}
+
+// NewInterningEncoder returns a new interning encoder that writes to w
+func NewInterningEncoder(w io.Writer) *InterningEncoder {
+	return &InterningEncoder{w, ProtocolVersion, map[string]int{}, 0}
+}
+
+// Version sets the protocol version spoken by the peer, see Encoder.Version
+func (e *InterningEncoder) Version(version int) {
+	e.version = version
+}
+
+// Encode writes the interned JSON encoding of the delta to the stream
+func (e *InterningEncoder) Encode(delta Delta) error {
+	if err := checkVersion(delta, e.version); err != nil {
+		return err
+	}
+
+	b := getBuffer()
+	defer putBuffer(b)
+
+	if err := writeJSONDelta(b, delta); err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(b.Bytes()))
+	decoder.UseNumber()
+
+	var input []interface{}
+	if err := decoder.Decode(&input); err != nil {
+		return err
+	}
+
+	// New entries are only recorded once the delta is written, so a failed
+	// write doesn't leave both ends out of sync
+	added := map[string]int{}
+	size := e.size
+
+	walkInterned(input, "$", func(value interface{}, path string) (interface{}, error) {
+		str, ok := value.(string)
+		if !ok {
+			return value, nil
+		}
+
+		id, ok := e.ids[str]
+		if !ok {
+			id, ok = added[str]
+		}
+
+		if !ok {
+			if count := len(e.ids) + len(added); internable(str, count, size) {
+				added[str] = count
+				size += len(str)
+			}
+
+			return value, nil
+		}
+
+		ref := strconv.Itoa(id)
+		if len(ref) >= len(str)+2 {
+			return value, nil
+		}
+
+		return json.Number(ref), nil
+	})
+
+	b.Reset()
+	writeJSONValue(b, input)
+
+	if _, err := e.w.Write(b.Bytes()); err != nil {
+		return err
+	}
+
+	for str, id := range added {
+		e.ids[str] = id
+	}
+
+	e.size = size
+	return nil
+}
+
+// InterningDecoder reads a stream of deltas written by an InterningEncoder
+type InterningDecoder struct {
+	dec     *json.Decoder
+	opts    decodeOptions
+	strings []string
+	known   map[string]bool
+	size    int
+}
+
+// NewInterningDecoder returns a new interning decoder that reads from r
+func NewInterningDecoder(r io.Reader) *InterningDecoder {
+	return &InterningDecoder{json.NewDecoder(r), decodeOptions{false, ProtocolVersion}, []string{}, map[string]bool{}, 0}
+}
+
+// Strict makes the decoder return a *DecodeError for malformed deltas
+// instead of skipping them, see UnmarshalStrict
+func (d *InterningDecoder) Strict() {
+	d.opts.strict = true
+}
+
+// Version sets the protocol version spoken by the peer, see Decoder.Version
+func (d *InterningDecoder) Version(version int) {
+	d.opts.version = version
+}
+
+// Decode reads the next delta from the stream, see Decoder.Decode. References
+// to unknown strings are always reported, since they mean both ends of the
+// stream are out of sync.
+func (d *InterningDecoder) Decode() (Delta, error) {
+	for {
+		var payload json.RawMessage
+		if err := d.dec.Decode(&payload); err != nil {
+			return nil, err
+		}
+
+		input, err := unmarshalJSONInput(payload)
+		if err != nil {
+			return nil, err
+		}
+
+		err = walkInterned(input, "$", func(value interface{}, path string) (interface{}, error) {
+			switch v := value.(type) {
+			case string:
+				if !d.known[v] && internable(v, len(d.strings), d.size) {
+					d.strings = append(d.strings, v)
+					d.known[v] = true
+					d.size += len(v)
+				}
+
+			case float64:
+				id := int(v)
+				if v != float64(id) || id < 0 || id >= len(d.strings) {
+					return nil, &DecodeError{Path: path, Msg: "unknown interned string " + strconv.FormatFloat(v, 'g', -1, 64)}
+				}
+
+				return d.strings[id], nil
+			}
+
+			return value, nil
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		delta, err := decodeJSONDelta(input, "$", d.opts)
+		if err != nil || delta != nil {
+			return delta, err
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package wit
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestInterning(t *testing.T) {
+	var b bytes.Buffer
+	encoder := NewInterningEncoder(&b)
+
+	metrics := First{S("#metrics .value"), List{[]Delta{
+		HTML{HTMLFromString("<b>1</b>")},
+		First{S("#metrics .value"), Append{HTMLFromString("<b>1</b>")}},
+		First{S("a"), Clear{}},
+	}}}
+
+	encoder.Encode(metrics)
+	encoder.Encode(metrics)
+	encoder.Encode(delta)
+
+	expected := `[3,"#metrics .value",[12,"<b>1</b>"],[3,0,[14,1]],[3,"a",[11]]][3,0,[12,1],[3,0,[14,1]],[3,2,[11]]]`
+	if got := b.String(); got[:len(expected)] != expected {
+		t.Error("Expected ", expected, ", got", got)
+	}
+
+	decoder := NewInterningDecoder(&b)
+	decoder.Strict()
+
+	for _, d := range []Delta{metrics, metrics, delta} {
+		expected, _ := d.MarshalJSON()
+
+		result, err := decoder.Decode()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resultJSON, _ := result.MarshalJSON()
+		if string(resultJSON) != string(expected) {
+			t.Error("Expected ", string(expected), ", got", string(resultJSON))
+		}
+	}
+
+	decoder = NewInterningDecoder(bytes.NewReader([]byte(`[3,"a",[12,1]]`)))
+	if _, err := decoder.Decode(); err == nil || err.Error() != "$[2][1]: unknown interned string 1" {
+		t.Error("Expected unknown string error, got", err)
+	}
+}
+
+func TestInterningRepeatedStrings(t *testing.T) {
+	var deltas []Delta
+	for i := 0; i < 12; i++ {
+		deltas = append(deltas, First{S(".item-" + strconv.Itoa(i)), Clear{}})
+	}
+
+	deltas = append(deltas,
+		HTML{HTMLFromString("")},
+		HTML{HTMLFromString("")},
+		First{S(".new"), Clear{}},
+		First{S(".new"), Clear{}},
+	)
+
+	var b bytes.Buffer
+	encoder := NewInterningEncoder(&b)
+	for _, d := range deltas {
+		if err := encoder.Encode(d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	decoder := NewInterningDecoder(&b)
+	decoder.Strict()
+
+	for _, d := range deltas {
+		expected, _ := d.MarshalJSON()
+
+		result, err := decoder.Decode()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resultJSON, _ := result.MarshalJSON()
+		if string(resultJSON) != string(expected) {
+			t.Error("Expected ", string(expected), ", got", string(resultJSON))
+		}
+	}
+}
+
+type failingWriter struct {
+	bytes.Buffer
+	fail bool
+}
+
+func (f *failingWriter) Write(p []byte) (int, error) {
+	if f.fail {
+		return 0, errors.New("write failed")
+	}
+
+	return f.Buffer.Write(p)
+}
+
+func TestInterningLimits(t *testing.T) {
+	long := HTML{HTMLFromString(strings.Repeat("x", maxInternedStringLength+1))}
+	short := First{S(".short"), Clear{}}
+	deltas := []Delta{long, long, short, short}
+
+	w := &failingWriter{fail: true}
+	encoder := NewInterningEncoder(w)
+	if err := encoder.Encode(First{S(".lost"), Clear{}}); err == nil {
+		t.Error("Expected write error")
+	}
+
+	w.fail = false
+	for _, d := range deltas {
+		if err := encoder.Encode(d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if strings.Count(w.String(), `"x`) != 2 || !strings.HasSuffix(w.String(), `[3,".short",[11]][3,0,[11]]`) {
+		t.Error("Expected long strings to be sent in full and .short to get the first id")
+	}
+
+	decoder := NewInterningDecoder(&w.Buffer)
+	decoder.Strict()
+
+	for _, d := range deltas {
+		expected, _ := d.MarshalJSON()
+
+		result, err := decoder.Decode()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resultJSON, _ := result.MarshalJSON()
+		if string(resultJSON) != string(expected) {
+			t.Error("Expected ", string(expected), ", got", string(resultJSON))
+		}
+	}
+}
@@ -1,65 +1,271 @@
 package wit
 
-import "strconv"
+import (
+	"strconv"
+	"strings"
+)
+
+// Op identifies the operation performed by a delta, its value is the label
+// the delta is sent with. Custom deltas use ops from FirstCustomLabel on.
+type Op int
 
+// Standard ops. They are frozen: once a protocol version is released its
+// labels are never renumbered nor reused, new deltas get new labels and a
+// new protocol version.
 const (
-	_ = iota
-
-	// Standard
-
-	listLabel
-
-	rootLabel
-	selectorLabel
-	selectorAllLabel
-	parentLabel
-	firstChildLabel
-	lastChildLabel
-	prevSiblingLabel
-	nextSiblingLabel
-
-	removeLabel
-	clearLabel
-
-	htmlLabel
-	replaceLabel
-	appendLabel
-	prependLabel
-	insertAfterLabel
-	insertBeforeLabel
-
-	setAttrLabel
-	replaceAttrLabel
-	rmAttrLabel
-	setStylesLabel
-	rmStylesLabel
-	addClassesLabel
-	rmClassesLabel
+	// Version 1
+
+	OpList Op = 1
+
+	OpRoot        Op = 2
+	OpFirst       Op = 3
+	OpAll         Op = 4
+	OpParent      Op = 5
+	OpFirstChild  Op = 6
+	OpLastChild   Op = 7
+	OpPrevSibling Op = 8
+	OpNextSibling Op = 9
+
+	OpRemove Op = 10
+	OpClear  Op = 11
+
+	OpHTML         Op = 12
+	OpReplace      Op = 13
+	OpAppend       Op = 14
+	OpPrepend      Op = 15
+	OpInsertAfter  Op = 16
+	OpInsertBefore Op = 17
+
+	OpSetAttr     Op = 18
+	OpReplaceAttr Op = 19
+	OpRmAttr      Op = 20
+	OpSetStyles   Op = 21
+	OpRmStyles    Op = 22
+	OpAddClasses  Op = 23
+	OpRmClasses   Op = 24
+
+	// Version 2
+
+	OpLoadScript     Op = 25
+	OpLoadStylesheet Op = 26
+
+	OpReconcileChildren Op = 27
+
+	OpFocus          Op = 28
+	OpBlur           Op = 29
+	OpScrollIntoView Op = 30
+	OpScrollTo       Op = 31
+	OpSelectText     Op = 32
+
+	OpDispatchEvent Op = 33
+
+	OpDelay Op = 34
+
+	OpRemoveWithTransition      Op = 35
+	OpAppendWithTransition      Op = 36
+	OpInsertAfterWithTransition Op = 37
+
+	OpPushState    Op = 38
+	OpReplaceState Op = 39
+	OpRedirect     Op = 40
+	OpReload       Op = 41
+
+	OpDefineTemplate Op = 42
+	OpUseTemplate    Op = 43
+
+	OpReplaceText Op = 44
+
+	OpRange Op = 45
+
+	OpAddTokens Op = 46
+	OpRmTokens  Op = 47
+
+	OpMergeHead Op = 48
 )
 
 var (
-	listLabelJSON         = strconv.Itoa(listLabel)
-	rootLabelJSON         = strconv.Itoa(rootLabel)
-	selectorLabelJSON     = strconv.Itoa(selectorLabel)
-	selectorAllLabelJSON  = strconv.Itoa(selectorAllLabel)
-	parentLabelJSON       = strconv.Itoa(parentLabel)
-	firstChildLabelJSON   = strconv.Itoa(firstChildLabel)
-	lastChildLabelJSON    = strconv.Itoa(lastChildLabel)
-	prevSiblingLabelJSON  = strconv.Itoa(prevSiblingLabel)
-	nextSiblingLabelJSON  = strconv.Itoa(nextSiblingLabel)
-	removeLabelJSON       = strconv.Itoa(removeLabel)
-	clearLabelJSON        = strconv.Itoa(clearLabel)
-	htmlLabelJSON         = strconv.Itoa(htmlLabel)
-	replaceLabelJSON      = strconv.Itoa(replaceLabel)
-	appendLabelJSON       = strconv.Itoa(appendLabel)
-	prependLabelJSON      = strconv.Itoa(prependLabel)
-	insertAfterLabelJSON  = strconv.Itoa(insertAfterLabel)
-	insertBeforeLabelJSON = strconv.Itoa(insertBeforeLabel)
-	setAttrLabelJSON      = strconv.Itoa(setAttrLabel)
-	replaceAttrLabelJSON  = strconv.Itoa(replaceAttrLabel)
-	rmAttrLabelJSON       = strconv.Itoa(rmAttrLabel)
-	setStylesLabelJSON    = strconv.Itoa(setStylesLabel)
-	rmStylesLabelJSON     = strconv.Itoa(rmStylesLabel)
-	addClassesLabelJSON   = strconv.Itoa(addClassesLabel)
-	rmClassesLabelJSON    = strconv.Itoa(rmClassesLabel)
+	listLabelJSON         = strconv.Itoa(int(OpList))
+	rootLabelJSON         = strconv.Itoa(int(OpRoot))
+	selectorLabelJSON     = strconv.Itoa(int(OpFirst))
+	selectorAllLabelJSON  = strconv.Itoa(int(OpAll))
+	parentLabelJSON       = strconv.Itoa(int(OpParent))
+	firstChildLabelJSON   = strconv.Itoa(int(OpFirstChild))
+	lastChildLabelJSON    = strconv.Itoa(int(OpLastChild))
+	prevSiblingLabelJSON  = strconv.Itoa(int(OpPrevSibling))
+	nextSiblingLabelJSON  = strconv.Itoa(int(OpNextSibling))
+	removeLabelJSON       = strconv.Itoa(int(OpRemove))
+	clearLabelJSON        = strconv.Itoa(int(OpClear))
+	htmlLabelJSON         = strconv.Itoa(int(OpHTML))
+	replaceLabelJSON      = strconv.Itoa(int(OpReplace))
+	appendLabelJSON       = strconv.Itoa(int(OpAppend))
+	prependLabelJSON      = strconv.Itoa(int(OpPrepend))
+	insertAfterLabelJSON  = strconv.Itoa(int(OpInsertAfter))
+	insertBeforeLabelJSON = strconv.Itoa(int(OpInsertBefore))
+	setAttrLabelJSON      = strconv.Itoa(int(OpSetAttr))
+	replaceAttrLabelJSON  = strconv.Itoa(int(OpReplaceAttr))
+	rmAttrLabelJSON       = strconv.Itoa(int(OpRmAttr))
+	setStylesLabelJSON    = strconv.Itoa(int(OpSetStyles))
+	rmStylesLabelJSON     = strconv.Itoa(int(OpRmStyles))
+	addClassesLabelJSON   = strconv.Itoa(int(OpAddClasses))
+	rmClassesLabelJSON    = strconv.Itoa(int(OpRmClasses))
+
+	loadScriptLabelJSON     = strconv.Itoa(int(OpLoadScript))
+	loadStylesheetLabelJSON = strconv.Itoa(int(OpLoadStylesheet))
+
+	reconcileChildrenLabelJSON = strconv.Itoa(int(OpReconcileChildren))
+
+	focusLabelJSON          = strconv.Itoa(int(OpFocus))
+	blurLabelJSON           = strconv.Itoa(int(OpBlur))
+	scrollIntoViewLabelJSON = strconv.Itoa(int(OpScrollIntoView))
+	scrollToLabelJSON       = strconv.Itoa(int(OpScrollTo))
+	selectTextLabelJSON     = strconv.Itoa(int(OpSelectText))
+
+	dispatchEventLabelJSON = strconv.Itoa(int(OpDispatchEvent))
+
+	delayLabelJSON = strconv.Itoa(int(OpDelay))
+
+	removeWithTransitionLabelJSON      = strconv.Itoa(int(OpRemoveWithTransition))
+	appendWithTransitionLabelJSON      = strconv.Itoa(int(OpAppendWithTransition))
+	insertAfterWithTransitionLabelJSON = strconv.Itoa(int(OpInsertAfterWithTransition))
+
+	pushStateLabelJSON    = strconv.Itoa(int(OpPushState))
+	replaceStateLabelJSON = strconv.Itoa(int(OpReplaceState))
+	redirectLabelJSON     = strconv.Itoa(int(OpRedirect))
+	reloadLabelJSON       = strconv.Itoa(int(OpReload))
+
+	defineTemplateLabelJSON = strconv.Itoa(int(OpDefineTemplate))
+	useTemplateLabelJSON    = strconv.Itoa(int(OpUseTemplate))
+
+	replaceTextLabelJSON = strconv.Itoa(int(OpReplaceText))
+
+	rangeLabelJSON = strconv.Itoa(int(OpRange))
+
+	addTokensLabelJSON = strconv.Itoa(int(OpAddTokens))
+	rmTokensLabelJSON  = strconv.Itoa(int(OpRmTokens))
+
+	mergeHeadLabelJSON = strconv.Itoa(int(OpMergeHead))
+)
+
+type argKind int
+
+const (
+	stringArg argKind = iota
+	selectorArg
+	htmlArg
+	nullableHTMLArg
+	intArg
+	millisArg
+	boolArg
+	strMapArg
+	jsonArg
+	deltaArg
 )
+
+type labelArg struct {
+	name     string
+	kind     argKind
+	optional bool
+}
+
+type labelSpec struct {
+	name    string
+	version int
+	args    []labelArg
+
+	// rest describes the arguments repeated after args, if any
+	rest []labelArg
+}
+
+var deltasRest = []labelArg{{"deltas", deltaArg, true}}
+
+// arg describes the i-th argument of the label, counting from 1
+func (spec labelSpec) arg(i int) (labelArg, bool) {
+	if i-1 < len(spec.args) {
+		return spec.args[i-1], true
+	}
+
+	if len(spec.rest) > 0 {
+		return spec.rest[(i-1-len(spec.args))%len(spec.rest)], true
+	}
+
+	return labelArg{}, false
+}
+
+// labels describes the standard labels: the protocol version which
+// introduced them and the shape of their arguments
+var labels = map[Op]labelSpec{
+	OpList:                      {"list", 1, nil, deltasRest},
+	OpRoot:                      {"root", 1, nil, deltasRest},
+	OpFirst:                     {"selector", 1, []labelArg{{"selector", selectorArg, false}}, deltasRest},
+	OpAll:                       {"selector all", 1, []labelArg{{"selector", selectorArg, false}}, deltasRest},
+	OpParent:                    {"parent", 1, nil, deltasRest},
+	OpFirstChild:                {"first child", 1, nil, deltasRest},
+	OpLastChild:                 {"last child", 1, nil, deltasRest},
+	OpPrevSibling:               {"prev sibling", 1, nil, deltasRest},
+	OpNextSibling:               {"next sibling", 1, nil, deltasRest},
+	OpRemove:                    {"remove", 1, nil, nil},
+	OpClear:                     {"clear", 1, nil, nil},
+	OpHTML:                      {"html", 1, []labelArg{{"html", htmlArg, false}}, nil},
+	OpReplace:                   {"replace", 1, []labelArg{{"html", htmlArg, false}}, nil},
+	OpAppend:                    {"append", 1, []labelArg{{"html", htmlArg, false}}, nil},
+	OpPrepend:                   {"prepend", 1, []labelArg{{"html", htmlArg, false}}, nil},
+	OpInsertAfter:               {"insert after", 1, []labelArg{{"html", htmlArg, false}}, nil},
+	OpInsertBefore:              {"insert before", 1, []labelArg{{"html", htmlArg, false}}, nil},
+	OpSetAttr:                   {"set attr", 1, []labelArg{{"attributes", strMapArg, false}}, nil},
+	OpReplaceAttr:               {"replace attr", 1, []labelArg{{"attributes", strMapArg, false}}, nil},
+	OpRmAttr:                    {"rm attr", 1, nil, []labelArg{{"attributes", stringArg, true}}},
+	OpSetStyles:                 {"set styles", 1, []labelArg{{"styles", strMapArg, false}}, nil},
+	OpRmStyles:                  {"rm styles", 1, nil, []labelArg{{"styles", stringArg, true}}},
+	OpAddClasses:                {"add classes", 1, []labelArg{{"classes", stringArg, false}}, nil},
+	OpRmClasses:                 {"rm classes", 1, []labelArg{{"classes", stringArg, false}}, nil},
+	OpLoadScript:                {"load script", 2, []labelArg{{"src", stringArg, false}, {"attributes", strMapArg, true}}, nil},
+	OpLoadStylesheet:            {"load stylesheet", 2, []labelArg{{"href", stringArg, false}, {"attributes", strMapArg, true}}, nil},
+	OpReconcileChildren:         {"reconcile children", 2, nil, []labelArg{{"key", stringArg, false}, {"html", nullableHTMLArg, false}}},
+	OpFocus:                     {"focus", 2, nil, nil},
+	OpBlur:                      {"blur", 2, nil, nil},
+	OpScrollIntoView:            {"scroll into view", 2, []labelArg{{"options", strMapArg, true}}, nil},
+	OpScrollTo:                  {"scroll to", 2, []labelArg{{"top", intArg, true}, {"left", intArg, true}}, nil},
+	OpSelectText:                {"select text", 2, []labelArg{{"start", intArg, true}, {"end", intArg, true}}, nil},
+	OpDispatchEvent:             {"dispatch event", 2, []labelArg{{"type", stringArg, false}, {"detail", jsonArg, true}, {"bubbles", boolArg, true}}, nil},
+	OpDelay:                     {"delay", 2, []labelArg{{"duration", millisArg, true}}, deltasRest},
+	OpRemoveWithTransition:      {"remove with transition", 2, []labelArg{{"classes", stringArg, false}, {"timeout", millisArg, true}}, nil},
+	OpAppendWithTransition:      {"append with transition", 2, []labelArg{{"html", htmlArg, false}, {"classes", stringArg, false}, {"timeout", millisArg, true}}, nil},
+	OpInsertAfterWithTransition: {"insert after with transition", 2, []labelArg{{"html", htmlArg, false}, {"classes", stringArg, false}, {"timeout", millisArg, true}}, nil},
+	OpPushState:                 {"push state", 2, []labelArg{{"url", stringArg, false}, {"title", stringArg, true}}, nil},
+	OpReplaceState:              {"replace state", 2, []labelArg{{"url", stringArg, false}, {"title", stringArg, true}}, nil},
+	OpRedirect:                  {"redirect", 2, []labelArg{{"url", stringArg, false}}, nil},
+	OpReload:                    {"reload", 2, nil, nil},
+	OpDefineTemplate:            {"define template", 2, []labelArg{{"name", stringArg, false}, {"html", htmlArg, false}}, nil},
+	OpUseTemplate:               {"use template", 2, []labelArg{{"name", stringArg, false}, {"params", strMapArg, true}}, nil},
+	OpReplaceText:               {"replace text", 2, []labelArg{{"find", stringArg, false}, {"replace", stringArg, true}, {"regex", boolArg, true}}, nil},
+	OpRange:                     {"range", 2, []labelArg{{"name", stringArg, false}}, deltasRest},
+	OpAddTokens:                 {"add tokens", 2, []labelArg{{"attr", stringArg, false}, {"tokens", stringArg, true}}, nil},
+	OpRmTokens:                  {"rm tokens", 2, []labelArg{{"attr", stringArg, false}, {"tokens", stringArg, true}}, nil},
+	OpMergeHead:                 {"merge head", 2, []labelArg{{"html", htmlArg, false}}, nil},
+}
+
+// opNames overrides the names derived from label names
+var opNames = map[Op]string{
+	OpFirst: "First",
+	OpAll:   "All",
+	OpHTML:  "HTML",
+}
+
+// String returns the name of the delta type performing the op
+func (op Op) String() string {
+	if name, ok := opNames[op]; ok {
+		return name
+	}
+
+	spec, ok := labels[op]
+	if !ok {
+		return "Op(" + strconv.Itoa(int(op)) + ")"
+	}
+
+	name := ""
+	for _, word := range strings.Fields(spec.name) {
+		name += strings.ToUpper(word[:1]) + word[1:]
+	}
+
+	return name
+}
@@ -1,6 +1,10 @@
 package wit
 
-import "golang.org/x/net/html"
+import (
+	"bytes"
+
+	"golang.org/x/net/html"
+)
 
 // LastChild applies given delta to the last child element
 type LastChild struct {
@@ -28,5 +32,31 @@ func (lc LastChild) Apply(d Document) {
 
 // MarshalJSON marshals the delta to JSON format
 func (lc LastChild) MarshalJSON() ([]byte, error) {
-	return []byte("[" + lastChildLabelJSON + deltaToCSV(lc.Delta) + "]"), nil
+	return marshalJSON(lc)
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (lc LastChild) MarshalBinary() ([]byte, error) {
+	return marshalBinary(lc)
+}
+
+// UnmarshalBinary sets *lc to the delta encoded in the binary format
+func (lc *LastChild) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, lc)
+}
+
+// Op returns the operation performed by the delta
+func (lc LastChild) Op() Op {
+	return OpLastChild
+}
+
+func (lc LastChild) writeJSON(b *bytes.Buffer) error {
+	writeJSONOpen(b, lastChildLabelJSON)
+
+	if err := writeJSONDeltaParameter(b, lc.Delta); err != nil {
+		return err
+	}
+
+	b.WriteByte(']')
+	return nil
 }
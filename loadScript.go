@@ -0,0 +1,51 @@
+package wit
+
+import (
+	"strconv"
+
+	"github.com/andybalholm/cascadia"
+)
+
+// LoadScript inserts a script into the head of the document unless one with
+// the same source is already present. Clients wait for the script to load
+// before applying the rest of the deltas.
+type LoadScript struct {
+	Src        string
+	Attributes map[string]string
+}
+
+// Apply applies the delta to the provided elements
+func (l LoadScript) Apply(d Document) {
+	if cascadia.Query(d.root, S("script[src="+quoteCSSString(l.Src)+"]")) != nil {
+		return
+	}
+
+	head := cascadia.Query(d.root, Head)
+	if head == nil {
+		return
+	}
+
+	for _, child := range HTMLFromString(buildTag("script", "src", l.Src, l.Attributes) + "</script>").Nodes(head) {
+		head.AppendChild(child)
+	}
+}
+
+// MarshalJSON marshals the delta to JSON format
+func (l LoadScript) MarshalJSON() ([]byte, error) {
+	return []byte("[" + loadScriptLabelJSON + "," + strconv.Quote(l.Src) + "," + strMapToJSON(l.Attributes) + "]"), nil
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (l LoadScript) MarshalBinary() ([]byte, error) {
+	return marshalBinary(l)
+}
+
+// UnmarshalBinary sets *l to the delta encoded in the binary format
+func (l *LoadScript) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, l)
+}
+
+// Op returns the operation performed by the delta
+func (l LoadScript) Op() Op {
+	return OpLoadScript
+}
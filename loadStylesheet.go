@@ -0,0 +1,57 @@
+package wit
+
+import (
+	"strconv"
+
+	"github.com/andybalholm/cascadia"
+)
+
+// LoadStylesheet inserts a stylesheet into the head of the document unless
+// one with the same URL is already present. Clients wait for the stylesheet
+// to load before applying the rest of the deltas.
+type LoadStylesheet struct {
+	Href       string
+	Attributes map[string]string
+}
+
+// Apply applies the delta to the provided elements
+func (l LoadStylesheet) Apply(d Document) {
+	if cascadia.Query(d.root, S("link[rel=stylesheet][href="+quoteCSSString(l.Href)+"]")) != nil {
+		return
+	}
+
+	head := cascadia.Query(d.root, Head)
+	if head == nil {
+		return
+	}
+
+	attr := map[string]string{}
+	for key, value := range l.Attributes {
+		attr[key] = value
+	}
+
+	attr["rel"] = "stylesheet"
+	for _, child := range HTMLFromString(buildTag("link", "href", l.Href, attr)).Nodes(head) {
+		head.AppendChild(child)
+	}
+}
+
+// MarshalJSON marshals the delta to JSON format
+func (l LoadStylesheet) MarshalJSON() ([]byte, error) {
+	return []byte("[" + loadStylesheetLabelJSON + "," + strconv.Quote(l.Href) + "," + strMapToJSON(l.Attributes) + "]"), nil
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (l LoadStylesheet) MarshalBinary() ([]byte, error) {
+	return marshalBinary(l)
+}
+
+// UnmarshalBinary sets *l to the delta encoded in the binary format
+func (l *LoadStylesheet) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, l)
+}
+
+// Op returns the operation performed by the delta
+func (l LoadStylesheet) Op() Op {
+	return OpLoadStylesheet
+}
@@ -0,0 +1,132 @@
+package wit
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// MergeHead reconciles the head of the document with the head of the
+// provided page. Elements present in both are kept untouched and new ones
+// are appended. Only stale meta elements are removed: scripts can't be
+// unloaded, and removing stylesheets before the new ones load would leave the
+// page unstyled. The title is updated in place.
+type MergeHead struct {
+	HTMLSource
+}
+
+// Apply applies the delta to the provided elements
+func (m MergeHead) Apply(d Document) {
+	head := cascadia.Query(d.root, Head)
+	if head == nil {
+		return
+	}
+
+	page, err := html.Parse(strings.NewReader(m.HTMLSource.String()))
+	if err != nil {
+		return
+	}
+
+	newHead := cascadia.Query(page, Head)
+	if newHead == nil {
+		return
+	}
+
+	newTitle := findChild(newHead, atom.Title)
+	if newTitle != nil {
+		newHead.RemoveChild(newTitle)
+
+		if title := findChild(head, atom.Title); title != nil {
+			for title.FirstChild != nil {
+				title.RemoveChild(title.FirstChild)
+			}
+
+			for newTitle.FirstChild != nil {
+				child := newTitle.FirstChild
+				newTitle.RemoveChild(child)
+				title.AppendChild(child)
+			}
+		} else if head.FirstChild != nil {
+			head.InsertBefore(newTitle, head.FirstChild)
+		} else {
+			head.AppendChild(newTitle)
+		}
+	}
+
+	wanted := map[string]int{}
+	for child := newHead.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode {
+			wanted[outerHTML(child)]++
+		}
+	}
+
+	for child := head.FirstChild; child != nil; {
+		next := child.NextSibling
+
+		if child.Type == html.ElementNode && child.DataAtom != atom.Title {
+			key := outerHTML(child)
+			if wanted[key] > 0 {
+				wanted[key]--
+			} else if child.DataAtom == atom.Meta {
+				head.RemoveChild(child)
+			}
+		}
+
+		child = next
+	}
+
+	for child := newHead.FirstChild; child != nil; {
+		next := child.NextSibling
+
+		if child.Type == html.ElementNode {
+			key := outerHTML(child)
+			if wanted[key] > 0 {
+				wanted[key]--
+				newHead.RemoveChild(child)
+				head.AppendChild(child)
+			}
+		}
+
+		child = next
+	}
+}
+
+// MarshalJSON marshals the delta to JSON format
+func (m MergeHead) MarshalJSON() ([]byte, error) {
+	return []byte("[" + mergeHeadLabelJSON + "," + strconv.Quote(m.HTMLSource.String()) + "]"), nil
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (m MergeHead) MarshalBinary() ([]byte, error) {
+	return marshalBinary(m)
+}
+
+// UnmarshalBinary sets *m to the delta encoded in the binary format
+func (m *MergeHead) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, m)
+}
+
+// Op returns the operation performed by the delta
+func (m MergeHead) Op() Op {
+	return OpMergeHead
+}
+
+func findChild(node *html.Node, a atom.Atom) *html.Node {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && child.DataAtom == a {
+			return child
+		}
+	}
+
+	return nil
+}
+
+func outerHTML(node *html.Node) string {
+	var b bytes.Buffer
+	html.Render(&b, node)
+	return b.String()
+}
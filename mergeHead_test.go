@@ -0,0 +1,23 @@
+package wit
+
+import "testing"
+
+func TestMergeHead(t *testing.T) {
+	doc := NewDocument()
+	First{Head, HTML{HTMLFromString(`<title>Old</title><meta name="description" content="old"><link rel="stylesheet" href="/app.css"><link rel="stylesheet" href="/old.css"><script src="/old.js"></script>`)}}.Apply(doc)
+
+	merge := MergeHead{HTMLFromString(`<!DOCTYPE html><html><head><title>New &amp; shiny</title><link rel="stylesheet" href="/app.css"><meta name="description" content="new"><link rel="stylesheet" href="/page.css"></head><body>ignored</body></html>`)}
+	merge.Apply(doc)
+
+	expected := `<!DOCTYPE html><html><head><title>New &amp; shiny</title><link rel="stylesheet" href="/app.css"/><link rel="stylesheet" href="/old.css"/><script src="/old.js"></script><meta name="description" content="new"/><link rel="stylesheet" href="/page.css"/></head><body></body></html>`
+	if result := render(doc); result != expected {
+		t.Error("Expected ", expected, ", got", result)
+	}
+
+	merge.Apply(doc)
+	if result := render(doc); result != expected {
+		t.Error("Expected ", expected, ", got", result)
+	}
+
+	assertJSONRoundTrip(t, List{[]Delta{merge}})
+}
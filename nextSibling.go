@@ -1,6 +1,10 @@
 package wit
 
-import "golang.org/x/net/html"
+import (
+	"bytes"
+
+	"golang.org/x/net/html"
+)
 
 // NextSibling applies given delta to the next sibling
 type NextSibling struct {
@@ -28,5 +32,31 @@ func (ns NextSibling) Apply(d Document) {
 
 // MarshalJSON marshals the delta to JSON format
 func (ns NextSibling) MarshalJSON() ([]byte, error) {
-	return []byte("[" + nextSiblingLabelJSON + deltaToCSV(ns.Delta) + "]"), nil
+	return marshalJSON(ns)
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (ns NextSibling) MarshalBinary() ([]byte, error) {
+	return marshalBinary(ns)
+}
+
+// UnmarshalBinary sets *ns to the delta encoded in the binary format
+func (ns *NextSibling) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, ns)
+}
+
+// Op returns the operation performed by the delta
+func (ns NextSibling) Op() Op {
+	return OpNextSibling
+}
+
+func (ns NextSibling) writeJSON(b *bytes.Buffer) error {
+	writeJSONOpen(b, nextSiblingLabelJSON)
+
+	if err := writeJSONDeltaParameter(b, ns.Delta); err != nil {
+		return err
+	}
+
+	b.WriteByte(']')
+	return nil
 }
@@ -1,6 +1,10 @@
 package wit
 
-import "golang.org/x/net/html"
+import (
+	"bytes"
+
+	"golang.org/x/net/html"
+)
 
 // Parent applies given delta to the parent element
 type Parent struct {
@@ -24,5 +28,31 @@ func (p Parent) Apply(d Document) {
 
 // MarshalJSON marshals the delta to JSON format
 func (p Parent) MarshalJSON() ([]byte, error) {
-	return []byte("[" + parentLabelJSON + deltaToCSV(p.Delta) + "]"), nil
+	return marshalJSON(p)
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (p Parent) MarshalBinary() ([]byte, error) {
+	return marshalBinary(p)
+}
+
+// UnmarshalBinary sets *p to the delta encoded in the binary format
+func (p *Parent) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, p)
+}
+
+// Op returns the operation performed by the delta
+func (p Parent) Op() Op {
+	return OpParent
+}
+
+func (p Parent) writeJSON(b *bytes.Buffer) error {
+	writeJSONOpen(b, parentLabelJSON)
+
+	if err := writeJSONDeltaParameter(b, p.Delta); err != nil {
+		return err
+	}
+
+	b.WriteByte(']')
+	return nil
 }
@@ -14,6 +14,11 @@ type Prepend struct {
 // Apply applies the delta to the provided elements
 func (p Prepend) Apply(d Document) {
 	for _, node := range d.nodes {
+		if isRangeStart(node) {
+			insertInRange(node, p.HTMLSource.Nodes(node.Parent), false)
+			continue
+		}
+
 		if node.Type != html.ElementNode {
 			continue
 		}
@@ -36,3 +41,18 @@ func (p Prepend) Apply(d Document) {
 func (p Prepend) MarshalJSON() ([]byte, error) {
 	return []byte("[" + prependLabelJSON + "," + strconv.Quote(p.HTMLSource.String()) + "]"), nil
 }
+
+// MarshalBinary marshals the delta to the binary format
+func (p Prepend) MarshalBinary() ([]byte, error) {
+	return marshalBinary(p)
+}
+
+// UnmarshalBinary sets *p to the delta encoded in the binary format
+func (p *Prepend) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, p)
+}
+
+// Op returns the operation performed by the delta
+func (p Prepend) Op() Op {
+	return OpPrepend
+}
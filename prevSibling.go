@@ -1,6 +1,10 @@
 package wit
 
-import "golang.org/x/net/html"
+import (
+	"bytes"
+
+	"golang.org/x/net/html"
+)
 
 // PrevSibling applies given delta to the previous sibling
 type PrevSibling struct {
@@ -28,5 +32,31 @@ func (ps PrevSibling) Apply(d Document) {
 
 // MarshalJSON marshals the delta to JSON format
 func (ps PrevSibling) MarshalJSON() ([]byte, error) {
-	return []byte("[" + prevSiblingLabelJSON + deltaToCSV(ps.Delta) + "]"), nil
+	return marshalJSON(ps)
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (ps PrevSibling) MarshalBinary() ([]byte, error) {
+	return marshalBinary(ps)
+}
+
+// UnmarshalBinary sets *ps to the delta encoded in the binary format
+func (ps *PrevSibling) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, ps)
+}
+
+// Op returns the operation performed by the delta
+func (ps PrevSibling) Op() Op {
+	return OpPrevSibling
+}
+
+func (ps PrevSibling) writeJSON(b *bytes.Buffer) error {
+	writeJSONOpen(b, prevSiblingLabelJSON)
+
+	if err := writeJSONDeltaParameter(b, ps.Delta); err != nil {
+		return err
+	}
+
+	b.WriteByte(']')
+	return nil
 }
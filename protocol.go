@@ -0,0 +1,99 @@
+package wit
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ProtocolVersion is the version of the wire format spoken by this package
+	ProtocolVersion = 2
+
+	// MinProtocolVersion is the oldest version of the wire format this
+	// package can still talk to
+	MinProtocolVersion = 1
+
+	// ProtocolHeader is the HTTP header clients use to announce the protocol
+	// version they speak. Clients not sending it speak version 1.
+	ProtocolHeader = "Wit-Protocol"
+)
+
+// NegotiateVersion returns the protocol version to use with a client which
+// sent the provided ProtocolHeader value
+func NegotiateVersion(header string) (int, error) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return MinProtocolVersion, nil
+	}
+
+	version, err := strconv.Atoi(header)
+	if err != nil {
+		return 0, errors.New("wit: malformed protocol version " + strconv.Quote(header))
+	}
+
+	if version < MinProtocolVersion {
+		return 0, errors.New("wit: unsupported protocol version " + header)
+	}
+
+	if version > ProtocolVersion {
+		return ProtocolVersion, nil
+	}
+
+	return version, nil
+}
+
+// checkVersion returns an error if the provided delta uses labels introduced
+// after the given protocol version
+func checkVersion(delta Delta, version int) error {
+	if version >= ProtocolVersion {
+		return nil
+	}
+
+	var err error
+	Walk(delta, &versionChecker{version, "$", 0, false, &err})
+	return err
+}
+
+func versionError(path string, spec labelSpec) *DecodeError {
+	return &DecodeError{Path: path, Msg: spec.name + " label requires protocol version " + strconv.Itoa(spec.version), hard: true}
+}
+
+// versionChecker visits the children of a delta, tracking the position of
+// each of them in the JSON encoding of their parent so errors carry the same
+// path the decoder would report
+type versionChecker struct {
+	version int
+	path    string
+	next    int
+	flatten bool
+	err     *error
+}
+
+func (c *versionChecker) Visit(delta Delta) Visitor {
+	if delta == nil || *c.err != nil {
+		return nil
+	}
+
+	// The child of a traversal is written as its trailing arguments, so
+	// the deltas of a list found there belong to the traversal itself
+	if _, ok := delta.(List); ok && c.flatten {
+		c.flatten = false
+		return c
+	}
+
+	path := c.path
+	if c.next > 0 {
+		path += "[" + strconv.Itoa(c.next) + "]"
+		c.next++
+	}
+
+	op := OpOf(delta)
+	spec := labels[op]
+	if spec.version > c.version {
+		*c.err = versionError(path+"[0]", spec)
+		return nil
+	}
+
+	return &versionChecker{c.version, path, 1 + len(spec.args), op != OpList, c.err}
+}
@@ -0,0 +1,116 @@
+package wit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// Labels must never change once released, update this test only to append
+// labels for a new protocol version
+func TestFrozenLabels(t *testing.T) {
+	frozen := map[int][]Op{
+		1: {
+			OpList, OpRoot, OpFirst, OpAll, OpParent,
+			OpFirstChild, OpLastChild, OpPrevSibling, OpNextSibling,
+			OpRemove, OpClear, OpHTML, OpReplace, OpAppend,
+			OpPrepend, OpInsertAfter, OpInsertBefore, OpSetAttr,
+			OpReplaceAttr, OpRmAttr, OpSetStyles, OpRmStyles,
+			OpAddClasses, OpRmClasses,
+		},
+		2: {
+			OpLoadScript, OpLoadStylesheet, OpReconcileChildren,
+			OpFocus, OpBlur, OpScrollIntoView, OpScrollTo,
+			OpSelectText, OpDispatchEvent, OpDelay,
+			OpRemoveWithTransition, OpAppendWithTransition,
+			OpInsertAfterWithTransition, OpPushState, OpReplaceState,
+			OpRedirect, OpReload, OpDefineTemplate, OpUseTemplate,
+			OpReplaceText, OpRange, OpAddTokens, OpRmTokens,
+			OpMergeHead,
+		},
+	}
+
+	expected := Op(1)
+	for version := 1; version <= ProtocolVersion; version++ {
+		for _, label := range frozen[version] {
+			if label != expected {
+				t.Error("Expected label ", expected, ", got", label)
+			}
+
+			if labels[label].version != version {
+				t.Error("Expected label ", label, " to belong to version ", version, ", got", labels[label].version)
+			}
+
+			expected++
+		}
+	}
+
+	if Op(len(labels)) != expected-1 {
+		t.Error("Expected ", expected-1, " labels, got", len(labels))
+	}
+}
+
+func TestNegotiateVersion(t *testing.T) {
+	cases := map[string]int{"": 1, "1": 1, " 2 ": 2, "99": ProtocolVersion}
+	for header, expected := range cases {
+		if version, err := NegotiateVersion(header); err != nil || version != expected {
+			t.Error("Expected ", expected, " for ", header, ", got", version, err)
+		}
+	}
+
+	for _, header := range []string{"0", "v2"} {
+		if _, err := NegotiateVersion(header); err == nil {
+			t.Error("Expected error for", header)
+		}
+	}
+}
+
+func TestVersionedEncoding(t *testing.T) {
+	var b bytes.Buffer
+	encoder := NewEncoder(&b)
+	encoder.Version(1)
+
+	if err := encoder.Encode(delta); err != nil {
+		t.Error(err)
+	}
+
+	err := encoder.Encode(List{[]Delta{Remove{}, First{Body, Focus{}}}})
+	if err == nil || err.Error() != "$[2][2][0]: focus label requires protocol version 2" {
+		t.Error("Expected version error, got", err)
+	}
+
+	if b.String() != expectedJSON {
+		t.Error("Expected ", expectedJSON, ", got", b.String())
+	}
+
+	decoder := NewDecoder(strings.NewReader(`[1,[10],[3,"body",[28]]]`))
+	decoder.Version(1)
+
+	if _, err := decoder.Decode(); err == nil {
+		t.Error("Expected version error")
+	}
+}
+
+func TestCheckVersion(t *testing.T) {
+	for _, delta := range []Delta{
+		List{[]Delta{Remove{}, First{Body, Focus{}}}},
+		First{Body, List{[]Delta{Remove{}, Parent{Focus{}}}}},
+		First{Body, List{[]Delta{Remove{}, List{[]Delta{Clear{}, Focus{}}}}}},
+		Root{Range{"items", Remove{}}},
+		Focus{},
+	} {
+		deltaJSON, _ := delta.MarshalJSON()
+		decoder := NewDecoder(bytes.NewReader(deltaJSON))
+		decoder.Version(1)
+
+		_, expected := decoder.Decode()
+		err := checkVersion(delta, 1)
+		if err == nil || expected == nil || err.Error() != expected.Error() {
+			t.Error("Expected", expected, "for", string(deltaJSON), ", got", err)
+		}
+	}
+
+	if err := checkVersion(List{[]Delta{Remove{}, First{Body, Clear{}}}}, 1); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,33 @@
+package wit
+
+import "strconv"
+
+// PushState adds an entry to the browser history with the provided URL and
+// title. It only has effect on the client.
+type PushState struct {
+	URL   string
+	Title string
+}
+
+// Apply applies the delta to the provided elements
+func (p PushState) Apply(d Document) {}
+
+// MarshalJSON marshals the delta to JSON format
+func (p PushState) MarshalJSON() ([]byte, error) {
+	return []byte("[" + pushStateLabelJSON + "," + strconv.Quote(p.URL) + "," + strconv.Quote(p.Title) + "]"), nil
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (p PushState) MarshalBinary() ([]byte, error) {
+	return marshalBinary(p)
+}
+
+// UnmarshalBinary sets *p to the delta encoded in the binary format
+func (p *PushState) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, p)
+}
+
+// Op returns the operation performed by the delta
+func (p PushState) Op() Op {
+	return OpPushState
+}
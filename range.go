@@ -0,0 +1,123 @@
+package wit
+
+import (
+	"bytes"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	rangeStartPrefix = "wit:start "
+	rangeEndPrefix   = "wit:end "
+)
+
+// Range applies given delta to the first range of sibling nodes delimited by
+// <!--wit:start name--> and <!--wit:end name--> comments. HTML, Clear, Append
+// and Prepend operate on the content of the range, other deltas see the
+// starting comment.
+type Range struct {
+	Name string
+	Delta
+}
+
+// Apply applies the delta to the provided elements
+func (r Range) Apply(d Document) {
+	childNodes := make([]*html.Node, 0, len(d.nodes))
+
+	for _, node := range d.nodes {
+		match := findRangeStart(node, r.Name)
+		if match != nil {
+			childNodes = append(childNodes, match)
+		}
+	}
+
+	d.nodes = childNodes
+	r.Delta.Apply(d)
+}
+
+// MarshalJSON marshals the delta to JSON format
+func (r Range) MarshalJSON() ([]byte, error) {
+	return marshalJSON(r)
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (r Range) MarshalBinary() ([]byte, error) {
+	return marshalBinary(r)
+}
+
+// UnmarshalBinary sets *r to the delta encoded in the binary format
+func (r *Range) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, r)
+}
+
+// Op returns the operation performed by the delta
+func (r Range) Op() Op {
+	return OpRange
+}
+
+func (r Range) writeJSON(b *bytes.Buffer) error {
+	writeJSONOpen(b, rangeLabelJSON)
+	writeJSONString(b, r.Name)
+
+	if err := writeJSONDeltaParameter(b, r.Delta); err != nil {
+		return err
+	}
+
+	b.WriteByte(']')
+	return nil
+}
+
+func findRangeStart(node *html.Node, name string) *html.Node {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.CommentNode && strings.TrimSpace(child.Data) == rangeStartPrefix+name {
+			return child
+		}
+
+		if match := findRangeStart(child, name); match != nil {
+			return match
+		}
+	}
+
+	return nil
+}
+
+func isRangeStart(node *html.Node) bool {
+	return node.Type == html.CommentNode && node.Parent != nil && strings.HasPrefix(strings.TrimSpace(node.Data), rangeStartPrefix)
+}
+
+// rangeEnd returns the comment closing the range opened by start, or nil if
+// the range extends up to the end of its parent
+func rangeEnd(start *html.Node) *html.Node {
+	end := rangeEndPrefix + strings.TrimPrefix(strings.TrimSpace(start.Data), rangeStartPrefix)
+
+	for sibling := start.NextSibling; sibling != nil; sibling = sibling.NextSibling {
+		if sibling.Type == html.CommentNode && strings.TrimSpace(sibling.Data) == end {
+			return sibling
+		}
+	}
+
+	return nil
+}
+
+func clearRange(start *html.Node) {
+	end := rangeEnd(start)
+	for start.NextSibling != nil && start.NextSibling != end {
+		start.Parent.RemoveChild(start.NextSibling)
+	}
+}
+
+func insertInRange(start *html.Node, children []*html.Node, atEnd bool) {
+	next := start.NextSibling
+	if atEnd {
+		next = rangeEnd(start)
+	}
+
+	for _, child := range children {
+		if next != nil {
+			start.Parent.InsertBefore(child, next)
+		} else {
+			start.Parent.AppendChild(child)
+		}
+	}
+}
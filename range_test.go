@@ -0,0 +1,30 @@
+package wit
+
+import "testing"
+
+func TestRange(t *testing.T) {
+	doc := NewDocument()
+	ranges := First{Body, List{[]Delta{
+		HTML{HTMLFromString(`<table><tbody><tr><td>head</td></tr><!--wit:start cart--><tr><td>old</td></tr><!--wit:end cart--><tr><td>foot</td></tr></tbody></table>`)},
+		Range{"cart", HTML{HTMLFromString(`<tr><td>one</td></tr>`)}},
+		Range{"cart", Append{HTMLFromString(`<tr><td>three</td></tr>`)}},
+		Range{"cart", Prepend{HTMLFromString(`<tr><td>zero</td></tr>`)}},
+		Range{"missing", Clear{}},
+	}}}
+
+	ranges.Apply(doc)
+
+	expected := `<!DOCTYPE html><html><head></head><body><table><tbody><tr><td>head</td></tr><!--wit:start cart--><tr><td>zero</td></tr><tr><td>one</td></tr><tr><td>three</td></tr><!--wit:end cart--><tr><td>foot</td></tr></tbody></table></body></html>`
+	if result := render(doc); result != expected {
+		t.Error("Expected ", expected, ", got", result)
+	}
+
+	First{Body, Range{"cart", Clear{}}}.Apply(doc)
+
+	expected = `<!DOCTYPE html><html><head></head><body><table><tbody><tr><td>head</td></tr><!--wit:start cart--><!--wit:end cart--><tr><td>foot</td></tr></tbody></table></body></html>`
+	if result := render(doc); result != expected {
+		t.Error("Expected ", expected, ", got", result)
+	}
+
+	assertJSONRoundTrip(t, List{[]Delta{ranges}})
+}
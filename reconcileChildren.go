@@ -0,0 +1,108 @@
+package wit
+
+import (
+	"bytes"
+
+	"golang.org/x/net/html"
+)
+
+const keyAttr = "data-key"
+
+// KeyedChild describes a child element identified by its data-key attribute.
+// The HTML source is only used when no existing child has the same key, and
+// may be nil if the child is known to exist.
+type KeyedChild struct {
+	Key string
+	HTMLSource
+}
+
+// ReconcileChildren makes the children of matching elements match the
+// provided keyed list, reusing and reordering existing children with a
+// matching data-key, inserting new ones and removing the rest
+type ReconcileChildren struct {
+	Children []KeyedChild
+}
+
+// Apply applies the delta to the provided elements
+func (r ReconcileChildren) Apply(d Document) {
+	for _, node := range d.nodes {
+		if node.Type != html.ElementNode {
+			continue
+		}
+
+		existing := map[string]*html.Node{}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if key, ok := getAttr(child, keyAttr); ok && child.Type == html.ElementNode {
+				if _, ok := existing[key]; !ok {
+					existing[key] = child
+				}
+			}
+		}
+
+		children := make([]*html.Node, 0, len(r.Children))
+		for _, keyed := range r.Children {
+			if child, ok := existing[keyed.Key]; ok {
+				delete(existing, keyed.Key)
+				children = append(children, child)
+				continue
+			}
+
+			if keyed.HTMLSource == nil {
+				continue
+			}
+
+			for _, child := range keyed.HTMLSource.Nodes(node) {
+				if child.Type == html.ElementNode {
+					setAttr(child, keyAttr, keyed.Key)
+					children = append(children, child)
+					break
+				}
+			}
+		}
+
+		for node.FirstChild != nil {
+			node.RemoveChild(node.FirstChild)
+		}
+
+		for _, child := range children {
+			node.AppendChild(child)
+		}
+	}
+}
+
+// MarshalJSON marshals the delta to JSON format
+func (r ReconcileChildren) MarshalJSON() ([]byte, error) {
+	return marshalJSON(r)
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (r ReconcileChildren) MarshalBinary() ([]byte, error) {
+	return marshalBinary(r)
+}
+
+// UnmarshalBinary sets *r to the delta encoded in the binary format
+func (r *ReconcileChildren) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, r)
+}
+
+// Op returns the operation performed by the delta
+func (r ReconcileChildren) Op() Op {
+	return OpReconcileChildren
+}
+
+func (r ReconcileChildren) writeJSON(b *bytes.Buffer) error {
+	writeJSONOpen(b, reconcileChildrenLabelJSON)
+
+	for _, keyed := range r.Children {
+		writeJSONString(b, keyed.Key)
+
+		if keyed.HTMLSource == nil {
+			b.WriteString(",null")
+		} else {
+			writeJSONString(b, keyed.HTMLSource.String())
+		}
+	}
+
+	b.WriteByte(']')
+	return nil
+}
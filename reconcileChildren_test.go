@@ -0,0 +1,30 @@
+package wit
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestReconcileChildren(t *testing.T) {
+	var b bytes.Buffer
+	doc := NewDocument()
+
+	First{Body, HTML{HTMLFromString(`<ul><li data-key="a" class="kept">A</li><li data-key="b">B</li>text<li data-key="c">C</li></ul>`)}}.Apply(doc)
+
+	reconcile := ReconcileChildren{[]KeyedChild{
+		{"c", nil},
+		{"d", HTMLFromString("<li>D</li>")},
+		{"a", HTMLFromString("<li>ignored</li>")},
+		{"e", nil},
+	}}
+
+	First{S("ul"), reconcile}.Apply(doc)
+	doc.Render(&b)
+
+	expected := `<!DOCTYPE html><html><head></head><body><ul><li data-key="c">C</li><li data-key="d">D</li><li data-key="a" class="kept">A</li></ul></body></html>`
+	if b.String() != expected {
+		t.Error("Expected ", expected, ", got", b.String())
+	}
+
+	assertJSONRoundTrip(t, List{[]Delta{reconcile}})
+}
@@ -0,0 +1,32 @@
+package wit
+
+import "strconv"
+
+// Redirect navigates the browser to the provided URL. It only has effect
+// on the client.
+type Redirect struct {
+	URL string
+}
+
+// Apply applies the delta to the provided elements
+func (r Redirect) Apply(d Document) {}
+
+// MarshalJSON marshals the delta to JSON format
+func (r Redirect) MarshalJSON() ([]byte, error) {
+	return []byte("[" + redirectLabelJSON + "," + strconv.Quote(r.URL) + "]"), nil
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (r Redirect) MarshalBinary() ([]byte, error) {
+	return marshalBinary(r)
+}
+
+// UnmarshalBinary sets *r to the delta encoded in the binary format
+func (r *Redirect) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, r)
+}
+
+// Op returns the operation performed by the delta
+func (r Redirect) Op() Op {
+	return OpRedirect
+}
@@ -0,0 +1,43 @@
+package wit
+
+import (
+	"strconv"
+	"sync"
+)
+
+// FirstCustomLabel is the first label reserved for application-defined
+// deltas, lower labels belong to the standard set
+const FirstCustomLabel = 1 << 16
+
+var (
+	registryMutex sync.RWMutex
+	registry      = map[Op]func([]interface{}) (Delta, error){}
+)
+
+// Register sets the decoder for the custom deltas with the provided label.
+// The decoder receives the whole JSON array, label included. Register panics
+// if the label is outside the reserved range or has already been registered.
+func Register(label Op, decoder func([]interface{}) (Delta, error)) {
+	if label < FirstCustomLabel {
+		panic("wit: label " + strconv.Itoa(int(label)) + " is reserved for standard deltas")
+	}
+
+	if decoder == nil {
+		panic("wit: nil decoder for label " + strconv.Itoa(int(label)))
+	}
+
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	if _, ok := registry[label]; ok {
+		panic("wit: label " + strconv.Itoa(int(label)) + " registered twice")
+	}
+
+	registry[label] = decoder
+}
+
+func registeredDecoder(label Op) func([]interface{}) (Delta, error) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	return registry[label]
+}
@@ -0,0 +1,62 @@
+package wit
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+const chartUpdateLabel = FirstCustomLabel + 1
+
+type chartUpdate struct {
+	Series string
+}
+
+func (c chartUpdate) Apply(d Document) {}
+
+func (c chartUpdate) MarshalJSON() ([]byte, error) {
+	return []byte("[" + strconv.Itoa(chartUpdateLabel) + "," + strconv.Quote(c.Series) + "]"), nil
+}
+
+func init() {
+	Register(chartUpdateLabel, func(input []interface{}) (Delta, error) {
+		if len(input) < 2 {
+			return nil, errors.New("missing series")
+		}
+
+		series, ok := input[1].(string)
+		if !ok {
+			return nil, errors.New("series must be a string")
+		}
+
+		return chartUpdate{series}, nil
+	})
+}
+
+func TestRegistry(t *testing.T) {
+	expected := `[1,[3,"#chart",[65537,"sales"]]]`
+
+	var result List
+	(&result).UnmarshalJSON([]byte(expected))
+	resultJSON, _ := result.MarshalJSON()
+
+	if string(resultJSON) != expected {
+		t.Error("Expected ", expected, ", got", string(resultJSON))
+	}
+
+	result = List{}
+	(&result).UnmarshalJSON([]byte(`[65537]`))
+	if len(result.Deltas) != 0 {
+		t.Error("Expected invalid custom delta to be dropped, got", result.Deltas)
+	}
+}
+
+func TestRegisterReserved(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected Register to panic for a standard label")
+		}
+	}()
+
+	Register(OpList, func(input []interface{}) (Delta, error) { return nil, nil })
+}
@@ -0,0 +1,27 @@
+package wit
+
+// Reload reloads the current page. It only has effect on the client.
+type Reload struct{}
+
+// Apply applies the delta to the provided elements
+func (r Reload) Apply(d Document) {}
+
+// MarshalJSON marshals the delta to JSON format
+func (r Reload) MarshalJSON() ([]byte, error) {
+	return []byte("[" + reloadLabelJSON + "]"), nil
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (r Reload) MarshalBinary() ([]byte, error) {
+	return marshalBinary(r)
+}
+
+// UnmarshalBinary sets *r to the delta encoded in the binary format
+func (r *Reload) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, r)
+}
+
+// Op returns the operation performed by the delta
+func (r Reload) Op() Op {
+	return OpReload
+}
@@ -17,3 +17,18 @@ func (r Remove) Apply(d Document) {
 func (r Remove) MarshalJSON() ([]byte, error) {
 	return []byte("[" + removeLabelJSON + "]"), nil
 }
+
+// MarshalBinary marshals the delta to the binary format
+func (r Remove) MarshalBinary() ([]byte, error) {
+	return marshalBinary(r)
+}
+
+// UnmarshalBinary sets *r to the delta encoded in the binary format
+func (r *Remove) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, r)
+}
+
+// Op returns the operation performed by the delta
+func (r Remove) Op() Op {
+	return OpRemove
+}
@@ -0,0 +1,39 @@
+package wit
+
+import (
+	"strconv"
+	"time"
+)
+
+// RemoveWithTransition removes matching elements after adding the provided
+// leave classes to them and waiting for their transition to end, or for the
+// timeout to expire. The server-side document is updated immediately.
+type RemoveWithTransition struct {
+	Classes string
+	Timeout time.Duration
+}
+
+// Apply applies the delta to the provided elements
+func (r RemoveWithTransition) Apply(d Document) {
+	Remove{}.Apply(d)
+}
+
+// MarshalJSON marshals the delta to JSON format
+func (r RemoveWithTransition) MarshalJSON() ([]byte, error) {
+	return []byte("[" + removeWithTransitionLabelJSON + "," + strconv.Quote(r.Classes) + "," + strconv.FormatInt(r.Timeout.Milliseconds(), 10) + "]"), nil
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (r RemoveWithTransition) MarshalBinary() ([]byte, error) {
+	return marshalBinary(r)
+}
+
+// UnmarshalBinary sets *r to the delta encoded in the binary format
+func (r *RemoveWithTransition) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, r)
+}
+
+// Op returns the operation performed by the delta
+func (r RemoveWithTransition) Op() Op {
+	return OpRemoveWithTransition
+}
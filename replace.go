@@ -31,3 +31,18 @@ func (r Replace) Apply(d Document) {
 func (r Replace) MarshalJSON() ([]byte, error) {
 	return []byte("[" + replaceLabelJSON + "," + strconv.Quote(r.HTMLSource.String()) + "]"), nil
 }
+
+// MarshalBinary marshals the delta to the binary format
+func (r Replace) MarshalBinary() ([]byte, error) {
+	return marshalBinary(r)
+}
+
+// UnmarshalBinary sets *r to the delta encoded in the binary format
+func (r *Replace) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, r)
+}
+
+// Op returns the operation performed by the delta
+func (r Replace) Op() Op {
+	return OpReplace
+}
@@ -15,15 +15,12 @@ func (r ReplaceAttr) Apply(d Document) {
 			continue
 		}
 
-		i := 0
 		nodeAttr := make([]html.Attribute, len(attr))
-		for key, value := range attr {
+		for i, key := range sortedKeys(attr) {
 			nodeAttr[i] = html.Attribute{
 				Key: key,
-				Val: value,
+				Val: attr[key],
 			}
-
-			i++
 		}
 
 		node.Attr = nodeAttr
@@ -34,3 +31,18 @@ func (r ReplaceAttr) Apply(d Document) {
 func (r ReplaceAttr) MarshalJSON() ([]byte, error) {
 	return []byte("[" + replaceAttrLabelJSON + "," + strMapToJSON(r.Attributes) + "]"), nil
 }
+
+// MarshalBinary marshals the delta to the binary format
+func (r ReplaceAttr) MarshalBinary() ([]byte, error) {
+	return marshalBinary(r)
+}
+
+// UnmarshalBinary sets *r to the delta encoded in the binary format
+func (r *ReplaceAttr) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, r)
+}
+
+// Op returns the operation performed by the delta
+func (r ReplaceAttr) Op() Op {
+	return OpReplaceAttr
+}
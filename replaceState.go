@@ -0,0 +1,33 @@
+package wit
+
+import "strconv"
+
+// ReplaceState replaces the current entry of the browser history with the
+// provided URL and title. It only has effect on the client.
+type ReplaceState struct {
+	URL   string
+	Title string
+}
+
+// Apply applies the delta to the provided elements
+func (r ReplaceState) Apply(d Document) {}
+
+// MarshalJSON marshals the delta to JSON format
+func (r ReplaceState) MarshalJSON() ([]byte, error) {
+	return []byte("[" + replaceStateLabelJSON + "," + strconv.Quote(r.URL) + "," + strconv.Quote(r.Title) + "]"), nil
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (r ReplaceState) MarshalBinary() ([]byte, error) {
+	return marshalBinary(r)
+}
+
+// UnmarshalBinary sets *r to the delta encoded in the binary format
+func (r *ReplaceState) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, r)
+}
+
+// Op returns the operation performed by the delta
+func (r ReplaceState) Op() Op {
+	return OpReplaceState
+}
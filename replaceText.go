@@ -0,0 +1,82 @@
+package wit
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ReplaceText replaces occurrences of Find with Replace in the text nodes
+// contained by matching elements, leaving the element structure untouched.
+// If Regex is set, Find is a regular expression and Replace may reference
+// its groups using $1 and the like. The contents of scripts, styles and
+// other raw text elements are left untouched.
+type ReplaceText struct {
+	Find    string
+	Replace string
+	Regex   bool
+}
+
+// Apply applies the delta to the provided elements
+func (r ReplaceText) Apply(d Document) {
+	if r.Find == "" {
+		return
+	}
+
+	replace := func(text string) string {
+		return strings.ReplaceAll(text, r.Find, r.Replace)
+	}
+
+	if r.Regex {
+		re, err := regexp.Compile(r.Find)
+		if err != nil {
+			return
+		}
+
+		replace = func(text string) string {
+			return re.ReplaceAllString(text, r.Replace)
+		}
+	}
+
+	for _, node := range d.nodes {
+		replaceText(node, replace)
+	}
+}
+
+func replaceText(node *html.Node, replace func(string) string) {
+	switch node.Type {
+	case html.TextNode:
+		node.Data = replace(node.Data)
+		return
+	case html.ElementNode:
+		if isRawText(node) {
+			return
+		}
+	}
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		replaceText(child, replace)
+	}
+}
+
+// MarshalJSON marshals the delta to JSON format
+func (r ReplaceText) MarshalJSON() ([]byte, error) {
+	return []byte("[" + replaceTextLabelJSON + "," + strconv.Quote(r.Find) + "," + strconv.Quote(r.Replace) + "," + strconv.FormatBool(r.Regex) + "]"), nil
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (r ReplaceText) MarshalBinary() ([]byte, error) {
+	return marshalBinary(r)
+}
+
+// UnmarshalBinary sets *r to the delta encoded in the binary format
+func (r *ReplaceText) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, r)
+}
+
+// Op returns the operation performed by the delta
+func (r ReplaceText) Op() Op {
+	return OpReplaceText
+}
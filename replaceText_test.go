@@ -0,0 +1,22 @@
+package wit
+
+import "testing"
+
+func TestReplaceText(t *testing.T) {
+	doc := NewDocument()
+	replacements := First{Body, List{[]Delta{
+		HTML{HTMLFromString(`<p title="teh">teh <b>teh</b> cat</p><script>var teh;</script><noscript>teh</noscript>`)},
+		ReplaceText{"teh", "the", false},
+		First{S("p"), ReplaceText{`(c)(at)`, "${1}o${2}", true}},
+		ReplaceText{"(", "", true},
+	}}}
+
+	replacements.Apply(doc)
+
+	expected := `<!DOCTYPE html><html><head></head><body><p title="teh">the <b>the</b> coat</p><script>var teh;</script><noscript>teh</noscript></body></html>`
+	if result := render(doc); result != expected {
+		t.Error("Expected ", expected, ", got", result)
+	}
+
+	assertJSONRoundTrip(t, List{[]Delta{replacements}})
+}
@@ -39,3 +39,18 @@ func (r RmAttr) Apply(d Document) {
 func (r RmAttr) MarshalJSON() ([]byte, error) {
 	return []byte("[" + rmAttrLabelJSON + strSliceToQuotedCSV(r.Attributes) + "]"), nil
 }
+
+// MarshalBinary marshals the delta to the binary format
+func (r RmAttr) MarshalBinary() ([]byte, error) {
+	return marshalBinary(r)
+}
+
+// UnmarshalBinary sets *r to the delta encoded in the binary format
+func (r *RmAttr) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, r)
+}
+
+// Op returns the operation performed by the delta
+func (r RmAttr) Op() Op {
+	return OpRmAttr
+}
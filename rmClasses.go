@@ -2,8 +2,6 @@ package wit
 
 import (
 	"strconv"
-
-	"golang.org/x/net/html"
 )
 
 // RmClasses removes provided classes from matching elements
@@ -13,39 +11,25 @@ type RmClasses struct {
 
 // Apply applies the delta to the provided elements
 func (r RmClasses) Apply(d Document) {
-	class := r.Classes
-	classesToRm := parseClass(class)
-
-	for _, node := range d.nodes {
-		if node.Type != html.ElementNode {
-			continue
-		}
-
-		for i, att := range node.Attr {
-			if att.Namespace != "" {
-				continue
-			}
-
-			if att.Key == "class" {
-				parsed := parseClass(att.Val)
-
-				for key, value := range classesToRm {
-					if value {
-						delete(parsed, key)
-					} else {
-						parsed[key] = true
-					}
-				}
-
-				att.Val = buildClass(parsed)
-				node.Attr[i] = att
-				break
-			}
-		}
-	}
+	RmTokens{"class", r.Classes}.Apply(d)
 }
 
 // MarshalJSON marshals the delta to JSON format
 func (r RmClasses) MarshalJSON() ([]byte, error) {
 	return []byte("[" + rmClassesLabelJSON + "," + strconv.Quote(r.Classes) + "]"), nil
 }
+
+// MarshalBinary marshals the delta to the binary format
+func (r RmClasses) MarshalBinary() ([]byte, error) {
+	return marshalBinary(r)
+}
+
+// UnmarshalBinary sets *r to the delta encoded in the binary format
+func (r *RmClasses) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, r)
+}
+
+// Op returns the operation performed by the delta
+func (r RmClasses) Op() Op {
+	return OpRmClasses
+}
@@ -24,7 +24,7 @@ func (r RmStyles) Apply(d Document) {
 			if att.Key == "style" {
 				parsed := parseStyle(att.Val)
 				for _, s := range styles {
-					delete(parsed, s)
+					parsed.remove(s)
 				}
 
 				att.Val = buildStyle(parsed)
@@ -39,3 +39,18 @@ func (r RmStyles) Apply(d Document) {
 func (r RmStyles) MarshalJSON() ([]byte, error) {
 	return []byte("[" + rmStylesLabelJSON + strSliceToQuotedCSV(r.Styles) + "]"), nil
 }
+
+// MarshalBinary marshals the delta to the binary format
+func (r RmStyles) MarshalBinary() ([]byte, error) {
+	return marshalBinary(r)
+}
+
+// UnmarshalBinary sets *r to the delta encoded in the binary format
+func (r *RmStyles) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, r)
+}
+
+// Op returns the operation performed by the delta
+func (r RmStyles) Op() Op {
+	return OpRmStyles
+}
@@ -0,0 +1,67 @@
+package wit
+
+import (
+	"strconv"
+
+	"golang.org/x/net/html"
+)
+
+// RmTokens removes provided tokens from the space-separated attribute of
+// matching elements, such as rel or aria-describedby
+type RmTokens struct {
+	Attr   string
+	Tokens string
+}
+
+// Apply applies the delta to the provided elements
+func (r RmTokens) Apply(d Document) {
+	tokensToRm := map[string]bool{}
+	for _, token := range parseTokens(r.Tokens) {
+		tokensToRm[token] = true
+	}
+
+	for _, node := range d.nodes {
+		if node.Type != html.ElementNode {
+			continue
+		}
+
+		for i, att := range node.Attr {
+			if att.Namespace != "" {
+				continue
+			}
+
+			if att.Key == r.Attr {
+				parsed := []string{}
+				for _, token := range parseTokens(att.Val) {
+					if !tokensToRm[token] {
+						parsed = append(parsed, token)
+					}
+				}
+
+				att.Val = buildTokens(parsed)
+				node.Attr[i] = att
+				break
+			}
+		}
+	}
+}
+
+// MarshalJSON marshals the delta to JSON format
+func (r RmTokens) MarshalJSON() ([]byte, error) {
+	return []byte("[" + rmTokensLabelJSON + "," + strconv.Quote(r.Attr) + "," + strconv.Quote(r.Tokens) + "]"), nil
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (r RmTokens) MarshalBinary() ([]byte, error) {
+	return marshalBinary(r)
+}
+
+// UnmarshalBinary sets *r to the delta encoded in the binary format
+func (r *RmTokens) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, r)
+}
+
+// Op returns the operation performed by the delta
+func (r RmTokens) Op() Op {
+	return OpRmTokens
+}
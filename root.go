@@ -1,6 +1,10 @@
 package wit
 
-import "golang.org/x/net/html"
+import (
+	"bytes"
+
+	"golang.org/x/net/html"
+)
 
 // Root applies given delta to the root of the document
 type Root struct {
@@ -15,5 +19,31 @@ func (r Root) Apply(d Document) {
 
 // MarshalJSON marshals the delta to JSON format
 func (r Root) MarshalJSON() ([]byte, error) {
-	return []byte("[" + rootLabelJSON + deltaToCSV(r.Delta) + "]"), nil
+	return marshalJSON(r)
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (r Root) MarshalBinary() ([]byte, error) {
+	return marshalBinary(r)
+}
+
+// UnmarshalBinary sets *r to the delta encoded in the binary format
+func (r *Root) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, r)
+}
+
+// Op returns the operation performed by the delta
+func (r Root) Op() Op {
+	return OpRoot
+}
+
+func (r Root) writeJSON(b *bytes.Buffer) error {
+	writeJSONOpen(b, rootLabelJSON)
+
+	if err := writeJSONDeltaParameter(b, r.Delta); err != nil {
+		return err
+	}
+
+	b.WriteByte(']')
+	return nil
 }
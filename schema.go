@@ -0,0 +1,206 @@
+package wit
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+//go:generate go run ./cmd/witgen -schema schema/wit.schema.json -ts schema/wit.d.ts
+
+// sortedLabels returns the standard labels in ascending order
+func sortedLabels() []Op {
+	result := make([]Op, 0, len(labels))
+	for label := Op(1); len(result) < len(labels); label++ {
+		if _, ok := labels[label]; ok {
+			result = append(result, label)
+		}
+	}
+
+	return result
+}
+
+func labelDescription(label Op) string {
+	spec := labels[label]
+	return label.String() + " (" + spec.name + " label, protocol version " + strconv.Itoa(spec.version) + ")"
+}
+
+func argSchema(arg labelArg) map[string]interface{} {
+	var schema map[string]interface{}
+
+	switch arg.kind {
+	case nullableHTMLArg:
+		schema = map[string]interface{}{"type": []string{"string", "null"}}
+	case intArg:
+		schema = map[string]interface{}{"type": "integer"}
+	case millisArg:
+		schema = map[string]interface{}{"type": "integer", "description": arg.name + ", in milliseconds"}
+	case boolArg:
+		schema = map[string]interface{}{"type": "boolean"}
+	case strMapArg:
+		schema = map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": map[string]interface{}{"type": "string"},
+		}
+	case jsonArg:
+		schema = map[string]interface{}{}
+	case deltaArg:
+		schema = map[string]interface{}{"$ref": "#/$defs/Delta"}
+	default:
+		schema = map[string]interface{}{"type": "string"}
+	}
+
+	if _, ok := schema["description"]; !ok {
+		schema["description"] = arg.name
+	}
+
+	return schema
+}
+
+// JSONSchema returns a JSON Schema describing the JSON wire format
+func JSONSchema() ([]byte, error) {
+	defs := map[string]interface{}{}
+	deltas := []interface{}{}
+
+	for _, label := range sortedLabels() {
+		spec := labels[label]
+
+		prefixItems := []interface{}{map[string]interface{}{"const": int(label)}}
+		minItems := 1
+		for _, arg := range spec.args {
+			prefixItems = append(prefixItems, argSchema(arg))
+			if !arg.optional {
+				minItems++
+			}
+		}
+
+		def := map[string]interface{}{
+			"description": labelDescription(label),
+			"type":        "array",
+			"prefixItems": prefixItems,
+			"minItems":    minItems,
+		}
+
+		switch len(spec.rest) {
+		case 0:
+			def["items"] = false
+		case 1:
+			def["items"] = argSchema(spec.rest[0])
+		default:
+			alternatives := []interface{}{}
+			for _, arg := range spec.rest {
+				alternatives = append(alternatives, argSchema(arg))
+			}
+
+			def["items"] = map[string]interface{}{"anyOf": alternatives}
+		}
+
+		defs[label.String()] = def
+		deltas = append(deltas, map[string]interface{}{"$ref": "#/$defs/" + label.String()})
+	}
+
+	defs["Delta"] = map[string]interface{}{"oneOf": deltas}
+
+	return json.MarshalIndent(map[string]interface{}{
+		"$schema":     "https://json-schema.org/draft/2020-12/schema",
+		"title":       "wit delta",
+		"description": "wit wire format, protocol version " + strconv.Itoa(ProtocolVersion),
+		"$ref":        "#/$defs/Delta",
+		"$defs":       defs,
+	}, "", "  ")
+}
+
+func argTypeScript(arg labelArg) string {
+	switch arg.kind {
+	case nullableHTMLArg:
+		return "string | null"
+	case intArg, millisArg:
+		return "number"
+	case boolArg:
+		return "boolean"
+	case strMapArg:
+		return "{ [key: string]: string }"
+	case jsonArg:
+		return "unknown"
+	case deltaArg:
+		return "Delta"
+	}
+
+	return "string"
+}
+
+func tsName(name string) string {
+	words := strings.Fields(name)
+	for i := 1; i < len(words); i++ {
+		words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+	}
+
+	return strings.Join(words, "")
+}
+
+// TypeScript returns TypeScript definitions describing the JSON wire format
+func TypeScript() string {
+	var b strings.Builder
+	labelList := sortedLabels()
+
+	b.WriteString("// Code generated by witgen. DO NOT EDIT.\n\n")
+	b.WriteString("export const PROTOCOL_VERSION = " + strconv.Itoa(ProtocolVersion) + ";\n\n")
+
+	b.WriteString("export const enum Label {\n")
+	for _, label := range labelList {
+		b.WriteString("  " + label.String() + " = " + strconv.Itoa(int(label)) + ",\n")
+	}
+
+	b.WriteString("}\n\nexport type Delta =\n")
+	for i, label := range labelList {
+		b.WriteString("  | " + label.String() + "Delta")
+		if i == len(labelList)-1 {
+			b.WriteString(";\n")
+		} else {
+			b.WriteString("\n")
+		}
+	}
+
+	for _, label := range labelList {
+		spec := labels[label]
+		items := []string{"label: Label." + label.String()}
+
+		for _, arg := range spec.args {
+			optional := ""
+			if arg.optional {
+				optional = "?"
+			}
+
+			items = append(items, tsName(arg.name)+optional+": "+argTypeScript(arg))
+		}
+
+		switch len(spec.rest) {
+		case 0:
+		case 1:
+			items = append(items, "..."+tsName(spec.rest[0].name)+": "+argTypeScript(spec.rest[0])+"[]")
+		default:
+			names := []string{}
+			types := []string{}
+			seen := map[string]bool{}
+			for _, arg := range spec.rest {
+				names = append(names, tsName(arg.name))
+				for _, t := range strings.Split(argTypeScript(arg), " | ") {
+					if !seen[t] {
+						seen[t] = true
+						types = append(types, t)
+					}
+				}
+			}
+
+			items = append(items, "...rest: ("+strings.Join(types, " | ")+")[]")
+			b.WriteString("\n/** " + labelDescription(label) + ", rest alternates " + strings.Join(names, ", ") + " */\n")
+			b.WriteString("export type " + label.String() + "Delta = [" + strings.Join(items, ", ") + "];\n")
+			continue
+		}
+
+		b.WriteString("\n/** " + labelDescription(label) + " */\n")
+		b.WriteString("export type " + label.String() + "Delta = [" + strings.Join(items, ", ") + "];\n")
+	}
+
+	return b.String()
+}
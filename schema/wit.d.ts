@@ -0,0 +1,248 @@
+// Code generated by witgen. DO NOT EDIT.
+
+export const PROTOCOL_VERSION = 2;
+
+export const enum Label {
+  List = 1,
+  Root = 2,
+  First = 3,
+  All = 4,
+  Parent = 5,
+  FirstChild = 6,
+  LastChild = 7,
+  PrevSibling = 8,
+  NextSibling = 9,
+  Remove = 10,
+  Clear = 11,
+  HTML = 12,
+  Replace = 13,
+  Append = 14,
+  Prepend = 15,
+  InsertAfter = 16,
+  InsertBefore = 17,
+  SetAttr = 18,
+  ReplaceAttr = 19,
+  RmAttr = 20,
+  SetStyles = 21,
+  RmStyles = 22,
+  AddClasses = 23,
+  RmClasses = 24,
+  LoadScript = 25,
+  LoadStylesheet = 26,
+  ReconcileChildren = 27,
+  Focus = 28,
+  Blur = 29,
+  ScrollIntoView = 30,
+  ScrollTo = 31,
+  SelectText = 32,
+  DispatchEvent = 33,
+  Delay = 34,
+  RemoveWithTransition = 35,
+  AppendWithTransition = 36,
+  InsertAfterWithTransition = 37,
+  PushState = 38,
+  ReplaceState = 39,
+  Redirect = 40,
+  Reload = 41,
+  DefineTemplate = 42,
+  UseTemplate = 43,
+  ReplaceText = 44,
+  Range = 45,
+  AddTokens = 46,
+  RmTokens = 47,
+  MergeHead = 48,
+}
+
+export type Delta =
+  | ListDelta
+  | RootDelta
+  | FirstDelta
+  | AllDelta
+  | ParentDelta
+  | FirstChildDelta
+  | LastChildDelta
+  | PrevSiblingDelta
+  | NextSiblingDelta
+  | RemoveDelta
+  | ClearDelta
+  | HTMLDelta
+  | ReplaceDelta
+  | AppendDelta
+  | PrependDelta
+  | InsertAfterDelta
+  | InsertBeforeDelta
+  | SetAttrDelta
+  | ReplaceAttrDelta
+  | RmAttrDelta
+  | SetStylesDelta
+  | RmStylesDelta
+  | AddClassesDelta
+  | RmClassesDelta
+  | LoadScriptDelta
+  | LoadStylesheetDelta
+  | ReconcileChildrenDelta
+  | FocusDelta
+  | BlurDelta
+  | ScrollIntoViewDelta
+  | ScrollToDelta
+  | SelectTextDelta
+  | DispatchEventDelta
+  | DelayDelta
+  | RemoveWithTransitionDelta
+  | AppendWithTransitionDelta
+  | InsertAfterWithTransitionDelta
+  | PushStateDelta
+  | ReplaceStateDelta
+  | RedirectDelta
+  | ReloadDelta
+  | DefineTemplateDelta
+  | UseTemplateDelta
+  | ReplaceTextDelta
+  | RangeDelta
+  | AddTokensDelta
+  | RmTokensDelta
+  | MergeHeadDelta;
+
+/** List (list label, protocol version 1) */
+export type ListDelta = [label: Label.List, ...deltas: Delta[]];
+
+/** Root (root label, protocol version 1) */
+export type RootDelta = [label: Label.Root, ...deltas: Delta[]];
+
+/** First (selector label, protocol version 1) */
+export type FirstDelta = [label: Label.First, selector: string, ...deltas: Delta[]];
+
+/** All (selector all label, protocol version 1) */
+export type AllDelta = [label: Label.All, selector: string, ...deltas: Delta[]];
+
+/** Parent (parent label, protocol version 1) */
+export type ParentDelta = [label: Label.Parent, ...deltas: Delta[]];
+
+/** FirstChild (first child label, protocol version 1) */
+export type FirstChildDelta = [label: Label.FirstChild, ...deltas: Delta[]];
+
+/** LastChild (last child label, protocol version 1) */
+export type LastChildDelta = [label: Label.LastChild, ...deltas: Delta[]];
+
+/** PrevSibling (prev sibling label, protocol version 1) */
+export type PrevSiblingDelta = [label: Label.PrevSibling, ...deltas: Delta[]];
+
+/** NextSibling (next sibling label, protocol version 1) */
+export type NextSiblingDelta = [label: Label.NextSibling, ...deltas: Delta[]];
+
+/** Remove (remove label, protocol version 1) */
+export type RemoveDelta = [label: Label.Remove];
+
+/** Clear (clear label, protocol version 1) */
+export type ClearDelta = [label: Label.Clear];
+
+/** HTML (html label, protocol version 1) */
+export type HTMLDelta = [label: Label.HTML, html: string];
+
+/** Replace (replace label, protocol version 1) */
+export type ReplaceDelta = [label: Label.Replace, html: string];
+
+/** Append (append label, protocol version 1) */
+export type AppendDelta = [label: Label.Append, html: string];
+
+/** Prepend (prepend label, protocol version 1) */
+export type PrependDelta = [label: Label.Prepend, html: string];
+
+/** InsertAfter (insert after label, protocol version 1) */
+export type InsertAfterDelta = [label: Label.InsertAfter, html: string];
+
+/** InsertBefore (insert before label, protocol version 1) */
+export type InsertBeforeDelta = [label: Label.InsertBefore, html: string];
+
+/** SetAttr (set attr label, protocol version 1) */
+export type SetAttrDelta = [label: Label.SetAttr, attributes: { [key: string]: string }];
+
+/** ReplaceAttr (replace attr label, protocol version 1) */
+export type ReplaceAttrDelta = [label: Label.ReplaceAttr, attributes: { [key: string]: string }];
+
+/** RmAttr (rm attr label, protocol version 1) */
+export type RmAttrDelta = [label: Label.RmAttr, ...attributes: string[]];
+
+/** SetStyles (set styles label, protocol version 1) */
+export type SetStylesDelta = [label: Label.SetStyles, styles: { [key: string]: string }];
+
+/** RmStyles (rm styles label, protocol version 1) */
+export type RmStylesDelta = [label: Label.RmStyles, ...styles: string[]];
+
+/** AddClasses (add classes label, protocol version 1) */
+export type AddClassesDelta = [label: Label.AddClasses, classes: string];
+
+/** RmClasses (rm classes label, protocol version 1) */
+export type RmClassesDelta = [label: Label.RmClasses, classes: string];
+
+/** LoadScript (load script label, protocol version 2) */
+export type LoadScriptDelta = [label: Label.LoadScript, src: string, attributes?: { [key: string]: string }];
+
+/** LoadStylesheet (load stylesheet label, protocol version 2) */
+export type LoadStylesheetDelta = [label: Label.LoadStylesheet, href: string, attributes?: { [key: string]: string }];
+
+/** ReconcileChildren (reconcile children label, protocol version 2), rest alternates key, html */
+export type ReconcileChildrenDelta = [label: Label.ReconcileChildren, ...rest: (string | null)[]];
+
+/** Focus (focus label, protocol version 2) */
+export type FocusDelta = [label: Label.Focus];
+
+/** Blur (blur label, protocol version 2) */
+export type BlurDelta = [label: Label.Blur];
+
+/** ScrollIntoView (scroll into view label, protocol version 2) */
+export type ScrollIntoViewDelta = [label: Label.ScrollIntoView, options?: { [key: string]: string }];
+
+/** ScrollTo (scroll to label, protocol version 2) */
+export type ScrollToDelta = [label: Label.ScrollTo, top?: number, left?: number];
+
+/** SelectText (select text label, protocol version 2) */
+export type SelectTextDelta = [label: Label.SelectText, start?: number, end?: number];
+
+/** DispatchEvent (dispatch event label, protocol version 2) */
+export type DispatchEventDelta = [label: Label.DispatchEvent, type: string, detail?: unknown, bubbles?: boolean];
+
+/** Delay (delay label, protocol version 2) */
+export type DelayDelta = [label: Label.Delay, duration?: number, ...deltas: Delta[]];
+
+/** RemoveWithTransition (remove with transition label, protocol version 2) */
+export type RemoveWithTransitionDelta = [label: Label.RemoveWithTransition, classes: string, timeout?: number];
+
+/** AppendWithTransition (append with transition label, protocol version 2) */
+export type AppendWithTransitionDelta = [label: Label.AppendWithTransition, html: string, classes: string, timeout?: number];
+
+/** InsertAfterWithTransition (insert after with transition label, protocol version 2) */
+export type InsertAfterWithTransitionDelta = [label: Label.InsertAfterWithTransition, html: string, classes: string, timeout?: number];
+
+/** PushState (push state label, protocol version 2) */
+export type PushStateDelta = [label: Label.PushState, url: string, title?: string];
+
+/** ReplaceState (replace state label, protocol version 2) */
+export type ReplaceStateDelta = [label: Label.ReplaceState, url: string, title?: string];
+
+/** Redirect (redirect label, protocol version 2) */
+export type RedirectDelta = [label: Label.Redirect, url: string];
+
+/** Reload (reload label, protocol version 2) */
+export type ReloadDelta = [label: Label.Reload];
+
+/** DefineTemplate (define template label, protocol version 2) */
+export type DefineTemplateDelta = [label: Label.DefineTemplate, name: string, html: string];
+
+/** UseTemplate (use template label, protocol version 2) */
+export type UseTemplateDelta = [label: Label.UseTemplate, name: string, params?: { [key: string]: string }];
+
+/** ReplaceText (replace text label, protocol version 2) */
+export type ReplaceTextDelta = [label: Label.ReplaceText, find: string, replace?: string, regex?: boolean];
+
+/** Range (range label, protocol version 2) */
+export type RangeDelta = [label: Label.Range, name: string, ...deltas: Delta[]];
+
+/** AddTokens (add tokens label, protocol version 2) */
+export type AddTokensDelta = [label: Label.AddTokens, attr: string, tokens?: string];
+
+/** RmTokens (rm tokens label, protocol version 2) */
+export type RmTokensDelta = [label: Label.RmTokens, attr: string, tokens?: string];
+
+/** MergeHead (merge head label, protocol version 2) */
+export type MergeHeadDelta = [label: Label.MergeHead, html: string];
@@ -0,0 +1,81 @@
+package wit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestGeneratedFilesUpToDate(t *testing.T) {
+	schema, err := JSONSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := ioutil.ReadFile("schema/wit.schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(append(schema, '\n'), file) {
+		t.Error("Expected schema/wit.schema.json to be up to date, run go generate")
+	}
+
+	file, err = ioutil.ReadFile("schema/wit.d.ts")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if TypeScript() != string(file) {
+		t.Error("Expected schema/wit.d.ts to be up to date, run go generate")
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	payload, err := JSONSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var schema struct {
+		Defs map[string]struct {
+			PrefixItems []map[string]interface{} `json:"prefixItems"`
+			MinItems    int                      `json:"minItems"`
+			Items       interface{}              `json:"items"`
+		} `json:"$defs"`
+	}
+
+	if err := json.Unmarshal(payload, &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(schema.Defs) != len(labels)+1 {
+		t.Fatal("Expected ", len(labels)+1, " definitions, got", len(schema.Defs))
+	}
+
+	first := schema.Defs["First"]
+	if first.PrefixItems[0]["const"] != float64(OpFirst) || first.MinItems != 2 {
+		t.Error("Unexpected First definition", first)
+	}
+
+	if schema.Defs["Remove"].Items != false {
+		t.Error("Expected Remove not to accept extra items")
+	}
+}
+
+func TestTypeScript(t *testing.T) {
+	ts := TypeScript()
+
+	for _, line := range []string{
+		"  InsertAfterWithTransition = 37,",
+		"export type FirstDelta = [label: Label.First, selector: string, ...deltas: Delta[]];",
+		"export type DelayDelta = [label: Label.Delay, duration?: number, ...deltas: Delta[]];",
+		"export type ReconcileChildrenDelta = [label: Label.ReconcileChildren, ...rest: (string | null)[]];",
+	} {
+		if !strings.Contains(ts, line+"\n") {
+			t.Error("Expected ", line, " in", ts)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package wit
+
+// ScrollIntoView scrolls the first matching element into view, using the
+// provided scrollIntoView options. It only has effect on the client.
+type ScrollIntoView struct {
+	Options map[string]string
+}
+
+// Apply applies the delta to the provided elements
+func (s ScrollIntoView) Apply(d Document) {}
+
+// MarshalJSON marshals the delta to JSON format
+func (s ScrollIntoView) MarshalJSON() ([]byte, error) {
+	return []byte("[" + scrollIntoViewLabelJSON + "," + strMapToJSON(s.Options) + "]"), nil
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (s ScrollIntoView) MarshalBinary() ([]byte, error) {
+	return marshalBinary(s)
+}
+
+// UnmarshalBinary sets *s to the delta encoded in the binary format
+func (s *ScrollIntoView) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, s)
+}
+
+// Op returns the operation performed by the delta
+func (s ScrollIntoView) Op() Op {
+	return OpScrollIntoView
+}
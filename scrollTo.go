@@ -0,0 +1,33 @@
+package wit
+
+import "strconv"
+
+// ScrollTo scrolls matching elements to the provided offsets, in pixels.
+// It only has effect on the client.
+type ScrollTo struct {
+	Top  int
+	Left int
+}
+
+// Apply applies the delta to the provided elements
+func (s ScrollTo) Apply(d Document) {}
+
+// MarshalJSON marshals the delta to JSON format
+func (s ScrollTo) MarshalJSON() ([]byte, error) {
+	return []byte("[" + scrollToLabelJSON + "," + strconv.Itoa(s.Top) + "," + strconv.Itoa(s.Left) + "]"), nil
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (s ScrollTo) MarshalBinary() ([]byte, error) {
+	return marshalBinary(s)
+}
+
+// UnmarshalBinary sets *s to the delta encoded in the binary format
+func (s *ScrollTo) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, s)
+}
+
+// Op returns the operation performed by the delta
+func (s ScrollTo) Op() Op {
+	return OpScrollTo
+}
@@ -0,0 +1,33 @@
+package wit
+
+import "strconv"
+
+// SelectText selects the text between the provided offsets of the first
+// matching input or textarea. It only has effect on the client.
+type SelectText struct {
+	Start int
+	End   int
+}
+
+// Apply applies the delta to the provided elements
+func (s SelectText) Apply(d Document) {}
+
+// MarshalJSON marshals the delta to JSON format
+func (s SelectText) MarshalJSON() ([]byte, error) {
+	return []byte("[" + selectTextLabelJSON + "," + strconv.Itoa(s.Start) + "," + strconv.Itoa(s.End) + "]"), nil
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (s SelectText) MarshalBinary() ([]byte, error) {
+	return marshalBinary(s)
+}
+
+// UnmarshalBinary sets *s to the delta encoded in the binary format
+func (s *SelectText) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, s)
+}
+
+// Op returns the operation performed by the delta
+func (s SelectText) Op() Op {
+	return OpSelectText
+}
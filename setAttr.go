@@ -30,7 +30,8 @@ func (s SetAttr) Apply(d Document) {
 			nodeAttr[att.Key] = i
 		}
 
-		for key, value := range attr {
+		for _, key := range sortedKeys(attr) {
+			value := attr[key]
 			i, ok := nodeAttr[key]
 			if ok {
 				node.Attr[i] = html.Attribute{
@@ -51,3 +52,18 @@ func (s SetAttr) Apply(d Document) {
 func (s SetAttr) MarshalJSON() ([]byte, error) {
 	return []byte("[" + setAttrLabelJSON + "," + strMapToJSON(s.Attributes) + "]"), nil
 }
+
+// MarshalBinary marshals the delta to the binary format
+func (s SetAttr) MarshalBinary() ([]byte, error) {
+	return marshalBinary(s)
+}
+
+// UnmarshalBinary sets *s to the delta encoded in the binary format
+func (s *SetAttr) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, s)
+}
+
+// Op returns the operation performed by the delta
+func (s SetAttr) Op() Op {
+	return OpSetAttr
+}
@@ -26,8 +26,8 @@ func (s SetStyles) Apply(d Document) {
 
 			if att.Key == "style" {
 				parsed := parseStyle(att.Val)
-				for key, value := range styles {
-					parsed[key] = value
+				for _, key := range sortedKeys(styles) {
+					parsed.set(key, styles[key])
 				}
 
 				att.Val = buildStyle(parsed)
@@ -38,9 +38,14 @@ func (s SetStyles) Apply(d Document) {
 		}
 
 		if !found {
+			parsed := &declarations{values: map[string]string{}}
+			for _, key := range sortedKeys(styles) {
+				parsed.set(key, styles[key])
+			}
+
 			node.Attr = append(node.Attr, html.Attribute{
 				Key: "style",
-				Val: buildStyle(styles),
+				Val: buildStyle(parsed),
 			})
 		}
 	}
@@ -51,3 +56,18 @@ func (s SetStyles) Apply(d Document) {
 func (s SetStyles) MarshalJSON() ([]byte, error) {
 	return []byte("[" + setStylesLabelJSON + "," + strMapToJSON(s.Styles) + "]"), nil
 }
+
+// MarshalBinary marshals the delta to the binary format
+func (s SetStyles) MarshalBinary() ([]byte, error) {
+	return marshalBinary(s)
+}
+
+// UnmarshalBinary sets *s to the delta encoded in the binary format
+func (s *SetStyles) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, s)
+}
+
+// Op returns the operation performed by the delta
+func (s SetStyles) Op() Op {
+	return OpSetStyles
+}
@@ -0,0 +1,46 @@
+package wit
+
+import "testing"
+
+func TestTemplates(t *testing.T) {
+	doc := NewDocument()
+	templates := List{[]Delta{
+		DefineTemplate{"row", HTMLFromString(`<li title="{{ title }}">{{name}}: {{missing}}</li>`)},
+		First{Body, HTML{HTMLFromString("<ul></ul>")}},
+		First{S("ul"), List{[]Delta{
+			UseTemplate{"row", map[string]string{"name": "<b>Bold</b>"}},
+			UseTemplate{"row", map[string]string{"title": `"quoted"`}},
+			UseTemplate{"unknown", nil},
+		}}},
+	}}
+
+	templates.Apply(doc)
+
+	expected := `<!DOCTYPE html><html><head></head><body><ul><li title="">&lt;b&gt;Bold&lt;/b&gt;: </li><li title="&#34;quoted&#34;">: </li></ul></body></html>`
+	if result := render(doc); result != expected {
+		t.Error("Expected ", expected, ", got", result)
+	}
+
+	other := NewDocument()
+	First{Body, UseTemplate{"row", nil}}.Apply(other)
+
+	expected = `<!DOCTYPE html><html><head></head><body></body></html>`
+	if result := render(other); result != expected {
+		t.Error("Expected ", expected, ", got", result)
+	}
+
+	assertJSONRoundTrip(t, templates)
+}
+
+func TestTemplateRawText(t *testing.T) {
+	doc := NewDocument()
+	First{Body, List{[]Delta{
+		DefineTemplate{"widget", HTMLFromString(`<div data-v="{{v}}"><script>var x = "{{v}}";</script><style>p { content: "{{v}}" }</style></div>`)},
+		UseTemplate{"widget", map[string]string{"v": `</script><img src=x onerror=alert(1)>`}},
+	}}}.Apply(doc)
+
+	expected := `<!DOCTYPE html><html><head></head><body><div data-v="&lt;/script&gt;&lt;img src=x onerror=alert(1)&gt;"><script>var x = "{{v}}";</script><style>p { content: "{{v}}" }</style></div></body></html>`
+	if result := render(doc); result != expected {
+		t.Error("Expected ", expected, ", got", result)
+	}
+}
@@ -0,0 +1,643 @@
+package wit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The text format is a human-readable equivalent of the JSON one, meant for
+// hand-written fixtures:
+//
+//	body > .two {
+//	  after "<div>"
+//	  next { attr foo=bar }
+//	}
+//
+// Each statement is a keyword followed by the arguments of its label, in
+// the same order as in JSON, and a block holding its child deltas if it
+// takes any. Trailing optional arguments may be omitted, except before a
+// block. Statements are separated by newlines or semicolons. A bare
+// selector followed by a block is a shorthand for first, even if it starts
+// with a keyword such as html or style, unless that keyword takes a block
+// itself: selectors like parent > p must be written as first parent > p.
+// Strings may be written as bare words or Go-style quoted strings, durations
+// as 300ms or 1s, and objects as key=value pairs.
+
+// textKeywords overrides the keywords derived from label names
+var textKeywords = map[Op]string{
+	OpFirst:        "first",
+	OpAll:          "all",
+	OpPrevSibling:  "prev",
+	OpNextSibling:  "next",
+	OpInsertAfter:  "after",
+	OpInsertBefore: "before",
+	OpSetAttr:      "attr",
+	OpSetStyles:    "style",
+	OpRmStyles:     "rm-style",
+	OpAddClasses:   "add-class",
+	OpRmClasses:    "rm-class",
+}
+
+var textLabels = map[string]Op{}
+
+func init() {
+	for label, spec := range labels {
+		textLabels[textKeyword(label, spec)] = label
+	}
+}
+
+func textKeyword(label Op, spec labelSpec) string {
+	if keyword, ok := textKeywords[label]; ok {
+		return keyword
+	}
+
+	return strings.ReplaceAll(spec.name, " ", "-")
+}
+
+func isTextSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\f'
+}
+
+func isTextDelimiter(c byte) bool {
+	switch c {
+	case '{', '}', ';', '=', '"', '`', '\n':
+		return true
+	}
+
+	return isTextSpace(c)
+}
+
+type textParser struct {
+	src  string
+	pos  int
+	line int
+}
+
+func (p *textParser) fail(msg string) error {
+	return errors.New("wit: line " + strconv.Itoa(p.line) + ": " + msg)
+}
+
+func (p *textParser) peek() byte {
+	if p.pos >= len(p.src) {
+		return 0
+	}
+
+	return p.src[p.pos]
+}
+
+func (p *textParser) skipSpace() {
+	for p.pos < len(p.src) && isTextSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *textParser) skipSeparators() {
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c == '\n' {
+			p.line++
+		} else if c != ';' && !isTextSpace(c) {
+			return
+		}
+
+		p.pos++
+	}
+}
+
+func (p *textParser) atStatementEnd() bool {
+	p.skipSpace()
+
+	switch p.peek() {
+	case 0, ';', '\n', '}':
+		return true
+	}
+
+	return false
+}
+
+func (p *textParser) readWord() string {
+	start := p.pos
+	for p.pos < len(p.src) && !isTextDelimiter(p.src[p.pos]) {
+		p.pos++
+	}
+
+	return p.src[start:p.pos]
+}
+
+// readString reads a bare word or a quoted string, reporting which one
+func (p *textParser) readString() (string, bool, error) {
+	p.skipSpace()
+
+	quote := p.peek()
+	if quote != '"' && quote != '`' {
+		word := p.readWord()
+		if word == "" {
+			return "", false, p.fail("expected string")
+		}
+
+		return word, false, nil
+	}
+
+	end := p.pos + 1
+	for ; end < len(p.src) && p.src[end] != quote; end++ {
+		if p.src[end] == '\\' && quote == '"' {
+			end++
+		}
+	}
+
+	if end >= len(p.src) {
+		return "", false, p.fail("unterminated string")
+	}
+
+	literal := p.src[p.pos : end+1]
+	str, err := strconv.Unquote(literal)
+	if err != nil {
+		return "", false, p.fail("malformed string " + literal)
+	}
+
+	p.line += strings.Count(literal, "\n")
+	p.pos = end + 1
+	return str, true, nil
+}
+
+// readSelector reads a quoted selector or the raw text up to the next block
+func (p *textParser) readSelector() (string, error) {
+	p.skipSpace()
+
+	if quote := p.peek(); quote == '"' || quote == '`' {
+		str, _, err := p.readString()
+		return str, err
+	}
+
+	end := strings.IndexAny(p.src[p.pos:], "{};\n")
+	if end == -1 || p.src[p.pos+end] != '{' {
+		return "", p.fail("expected { after selector")
+	}
+
+	selector := strings.TrimSpace(p.src[p.pos : p.pos+end])
+	if selector == "" {
+		return "", p.fail("expected selector")
+	}
+
+	p.pos += end
+	return selector, nil
+}
+
+func (p *textParser) parseArg(arg labelArg) (interface{}, error) {
+	switch arg.kind {
+	case selectorArg:
+		return p.readSelector()
+
+	case strMapArg:
+		strMap := map[string]interface{}{}
+		for !p.atStatementEnd() && p.peek() != '{' {
+			key, _, err := p.readString()
+			if err != nil {
+				return nil, err
+			}
+
+			if p.peek() != '=' {
+				return nil, p.fail("expected = after " + key)
+			}
+
+			p.pos++
+			value, _, err := p.readString()
+			if err != nil {
+				return nil, err
+			}
+
+			strMap[key] = value
+		}
+
+		return strMap, nil
+	}
+
+	str, quoted, err := p.readString()
+	if err != nil {
+		return nil, err
+	}
+
+	switch arg.kind {
+	case nullableHTMLArg:
+		if !quoted && str == "null" {
+			return nil, nil
+		}
+
+	case intArg:
+		number, err := strconv.Atoi(str)
+		if err != nil {
+			return nil, p.fail(arg.name + " requires an integer")
+		}
+
+		return float64(number), nil
+
+	case millisArg:
+		if number, err := strconv.Atoi(str); err == nil {
+			return float64(number), nil
+		}
+
+		duration, err := time.ParseDuration(str)
+		if err != nil {
+			return nil, p.fail(arg.name + " requires a duration")
+		}
+
+		return float64(duration.Milliseconds()), nil
+
+	case boolArg:
+		value, err := strconv.ParseBool(str)
+		if err != nil {
+			return nil, p.fail(arg.name + " requires true or false")
+		}
+
+		return value, nil
+
+	case jsonArg:
+		if !quoted && str == "null" {
+			return nil, nil
+		}
+
+		var value json.RawMessage
+		if err := json.Unmarshal([]byte(str), &value); err != nil {
+			return nil, p.fail(arg.name + " requires JSON: " + err.Error())
+		}
+
+		return value, nil
+	}
+
+	return str, nil
+}
+
+func (p *textParser) parseBlock() ([]interface{}, error) {
+	p.skipSpace()
+	if p.peek() != '{' {
+		return nil, nil
+	}
+
+	p.pos++
+	return p.parseStatements(true)
+}
+
+func (p *textParser) parseStatement() ([]interface{}, error) {
+	start, line := p.pos, p.line
+	label, ok := textLabels[p.readWord()]
+	if !ok {
+		p.pos = start
+		return p.parseArgs(OpFirst)
+	}
+
+	input, err := p.parseArgs(label)
+	if err != nil && !takesTextBlock(label) {
+		p.pos, p.line = start, line
+		if first, firstErr := p.parseArgs(OpFirst); firstErr == nil {
+			return first, nil
+		}
+	}
+
+	return input, err
+}
+
+// takesTextBlock reports whether statements of the label may be followed by
+// a block, so a selector starting with its keyword can't be written bare
+func takesTextBlock(label Op) bool {
+	rest := labels[label].rest
+	return len(rest) > 0 && rest[0].kind == deltaArg
+}
+
+func (p *textParser) parseArgs(label Op) ([]interface{}, error) {
+	spec := labels[label]
+	input := []interface{}{float64(label)}
+
+	for _, arg := range spec.args {
+		if arg.kind != strMapArg && (p.atStatementEnd() || p.peek() == '{') {
+			if !arg.optional || len(spec.rest) > 0 {
+				return nil, p.fail(textKeyword(label, spec) + " requires " + arg.name)
+			}
+
+			break
+		}
+
+		value, err := p.parseArg(arg)
+		if err != nil {
+			return nil, err
+		}
+
+		input = append(input, value)
+	}
+
+	if len(input) == len(spec.args)+1 && len(spec.rest) > 0 {
+		if spec.rest[0].kind == deltaArg {
+			children, err := p.parseBlock()
+			if err != nil {
+				return nil, err
+			}
+
+			input = append(input, children...)
+		} else {
+			for i := 0; !p.atStatementEnd(); i++ {
+				value, err := p.parseArg(spec.rest[i%len(spec.rest)])
+				if err != nil {
+					return nil, err
+				}
+
+				input = append(input, value)
+			}
+		}
+	}
+
+	if !p.atStatementEnd() {
+		if word := p.readWord(); word != "" {
+			return nil, p.fail("unexpected " + strconv.Quote(word))
+		}
+
+		return nil, p.fail("unexpected " + strconv.QuoteRune(rune(p.peek())))
+	}
+
+	return input, nil
+}
+
+func (p *textParser) parseStatements(inBlock bool) ([]interface{}, error) {
+	statements := []interface{}{}
+
+	for {
+		p.skipSeparators()
+
+		switch p.peek() {
+		case 0:
+			if inBlock {
+				return nil, p.fail("expected }")
+			}
+
+			return statements, nil
+
+		case '}':
+			if !inBlock {
+				return nil, p.fail("unexpected }")
+			}
+
+			p.pos++
+			return statements, nil
+		}
+
+		statement, err := p.parseStatement()
+		if err != nil {
+			return nil, err
+		}
+
+		statements = append(statements, statement)
+	}
+}
+
+// ParseText parses deltas written in the text format
+func ParseText(src string) (List, error) {
+	p := &textParser{src, 0, 1}
+
+	statements, err := p.parseStatements(false)
+	if err != nil {
+		return List{}, err
+	}
+
+	delta, err := decodeJSONDelta(append([]interface{}{float64(OpList)}, statements...), "$", decodeOptions{true, ProtocolVersion})
+	if err != nil {
+		return List{}, err
+	}
+
+	return delta.(List), nil
+}
+
+func isTextWord(str string) bool {
+	if str == "" {
+		return false
+	}
+
+	for i := 0; i < len(str); i++ {
+		if isTextDelimiter(str[i]) || str[i] == '\\' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func formatTextString(b *bytes.Buffer, str string) {
+	if isTextWord(str) && str != "null" {
+		b.WriteString(str)
+	} else {
+		b.WriteString(strconv.Quote(str))
+	}
+}
+
+// isTextSelector reports whether the selector can be written unquoted
+func isTextSelector(selector string) bool {
+	return selector != "" &&
+		selector == strings.TrimSpace(selector) &&
+		!strings.ContainsAny(selector, "{};\n") &&
+		selector[0] != '"' && selector[0] != '`'
+}
+
+func firstTextWord(str string) string {
+	for i := 0; i < len(str); i++ {
+		if isTextDelimiter(str[i]) {
+			return str[:i]
+		}
+	}
+
+	return str
+}
+
+func isZeroTextArg(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case json.Number:
+		return v == "0"
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+
+	return false
+}
+
+func formatTextArg(b *bytes.Buffer, arg labelArg, value interface{}) error {
+	switch arg.kind {
+	case selectorArg:
+		selector, _ := value.(string)
+		if isTextSelector(selector) {
+			b.WriteString(selector)
+		} else {
+			b.WriteString(strconv.Quote(selector))
+		}
+
+	case nullableHTMLArg, jsonArg:
+		if value == nil {
+			b.WriteString("null")
+		} else if str, ok := value.(string); ok && arg.kind == nullableHTMLArg {
+			formatTextString(b, str)
+		} else {
+			raw, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+
+			b.WriteString(strconv.Quote(string(raw)))
+		}
+
+	case millisArg:
+		number, _ := value.(json.Number)
+		millis, err := number.Int64()
+		if err != nil {
+			return err
+		}
+
+		b.WriteString((time.Duration(millis) * time.Millisecond).String())
+
+	case strMapArg:
+		strMap, _ := value.(map[string]interface{})
+		keys := make([]string, 0, len(strMap))
+		for key := range strMap {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for i, key := range keys {
+			if i != 0 {
+				b.WriteByte(' ')
+			}
+
+			str, _ := strMap[key].(string)
+			formatTextString(b, key)
+			b.WriteByte('=')
+			formatTextString(b, str)
+		}
+
+	default:
+		switch v := value.(type) {
+		case string:
+			formatTextString(b, v)
+		case json.Number:
+			b.WriteString(string(v))
+		case bool:
+			b.WriteString(strconv.FormatBool(v))
+		}
+	}
+
+	return nil
+}
+
+func formatTextStatement(b *bytes.Buffer, input []interface{}, indent string) error {
+	label, ok := labelOf(firstValue(input))
+	spec, known := labels[label]
+	if !ok || !known {
+		return errors.New("wit: label " + strconv.Itoa(int(label)) + " has no text format")
+	}
+
+	b.WriteString(indent)
+
+	args := input[1:]
+	if len(args) > len(spec.args) {
+		args = args[:len(spec.args)]
+	}
+
+	selector, _ := firstValue(args).(string)
+	if label == OpFirst && isTextSelector(selector) && !takesTextBlock(textLabels[firstTextWord(selector)]) {
+		b.WriteString(selector)
+	} else {
+		b.WriteString(textKeyword(label, spec))
+
+		if len(spec.rest) == 0 {
+			for len(args) > 0 && spec.args[len(args)-1].optional && isZeroTextArg(args[len(args)-1]) {
+				args = args[:len(args)-1]
+			}
+		}
+
+		for i, value := range args {
+			if spec.args[i].kind == strMapArg && isZeroTextArg(value) {
+				continue
+			}
+
+			b.WriteByte(' ')
+			if err := formatTextArg(b, spec.args[i], value); err != nil {
+				return err
+			}
+		}
+	}
+
+	rest := []interface{}{}
+	if len(input) > len(spec.args)+1 {
+		rest = input[len(spec.args)+1:]
+	}
+
+	if len(spec.rest) == 0 {
+		b.WriteByte('\n')
+		return nil
+	}
+
+	if spec.rest[0].kind != deltaArg {
+		for i, value := range rest {
+			b.WriteByte(' ')
+			if err := formatTextArg(b, spec.rest[i%len(spec.rest)], value); err != nil {
+				return err
+			}
+		}
+
+		b.WriteByte('\n')
+		return nil
+	}
+
+	if len(rest) == 0 {
+		b.WriteString(" {}\n")
+		return nil
+	}
+
+	b.WriteString(" {\n")
+	for _, child := range rest {
+		childInput, _ := child.([]interface{})
+		if err := formatTextStatement(b, childInput, indent+"  "); err != nil {
+			return err
+		}
+	}
+
+	b.WriteString(indent + "}\n")
+	return nil
+}
+
+// FormatText formats the provided delta in the text format. Lists are
+// written as a sequence of statements.
+func FormatText(delta Delta) (string, error) {
+	deltaJSON, err := delta.MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(deltaJSON))
+	decoder.UseNumber()
+
+	var input []interface{}
+	if err := decoder.Decode(&input); err != nil {
+		return "", err
+	}
+
+	statements := []interface{}{input}
+	if label, _ := labelOf(firstValue(input)); label == OpList {
+		statements = input[1:]
+	}
+
+	var b bytes.Buffer
+	for _, statement := range statements {
+		statementInput, _ := statement.([]interface{})
+		if err := formatTextStatement(&b, statementInput, ""); err != nil {
+			return "", err
+		}
+	}
+
+	return b.String(), nil
+}
@@ -0,0 +1,152 @@
+package wit
+
+import (
+	"testing"
+	"time"
+)
+
+var expectedText = `body {
+  html "<div class=\"one\"></div>"
+  append "<div class=\"two\"></div>"
+  prepend "<h1 foo=bar></h1>"
+  .two {
+    after "<div class=\"three\" style=\"background: black;border: 1px solid\"></div>"
+    next {
+      attr foo=bar
+    }
+    prev {
+      replace-attr number=one
+    }
+    parent {
+      add-class "foo bar"
+    }
+    root {
+      body {
+        rm-class "baz foo"
+        first-child {
+          style color=black
+        }
+        last-child {
+          rm-style background
+        }
+      }
+    }
+  }
+  all div {
+    before <hr>
+  }
+  h1 {
+    replace-attr bar=foo
+    attr bar2=foo2
+    rm-attr bar2
+    attr bar3=foo3
+  }
+}
+`
+
+func TestFormatText(t *testing.T) {
+	result, err := FormatText(delta)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result != expectedText {
+		t.Error("Expected ", expectedText, ", got", result)
+	}
+}
+
+func TestParseText(t *testing.T) {
+	result, err := ParseText(expectedText)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resultJSON, _ := result.MarshalJSON()
+	if string(resultJSON) != expectedJSON {
+		t.Error("Expected ", expectedJSON, ", got", string(resultJSON))
+	}
+
+	result, err = ParseText(`body > .two { after "<div>"; next { attr foo=bar } }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `[1,[3,"body > .two",[16,"<div>"],[9,[18,{"foo":"bar"}]]]]`
+	if resultJSON, _ := result.MarshalJSON(); string(resultJSON) != expected {
+		t.Error("Expected ", expected, ", got", string(resultJSON))
+	}
+}
+
+func TestTextRoundTrip(t *testing.T) {
+	deltas := List{[]Delta{
+		First{S("html"), Clear{}},
+		First{S("html > body"), Clear{}},
+		First{S("style"), SetStyles{map[string]string{"color": "red"}}},
+		First{S("parent > p"), Clear{}},
+		First{S(`a[title="{x}"]`), All{S("attr=x"), Remove{}}},
+		Range{"cart rows", Delay{1500 * time.Millisecond, List{}}},
+		LoadScript{"/chart.js", map[string]string{"data-x": "a b"}},
+		LoadStylesheet{"/chart.css", nil},
+		ReconcileChildren{[]KeyedChild{{"a", nil}, {"null", HTMLFromString("null")}}},
+		ScrollIntoView{map[string]string{"block": "center"}},
+		ScrollTo{-10, 0},
+		SelectText{0, 5},
+		Focus{},
+		Blur{},
+		DispatchEvent{"update", []byte(`{"a":[1,"x"]}`), true},
+		DispatchEvent{"ping", nil, false},
+		DispatchEvent{"str", []byte(`"null"`), false},
+		RemoveWithTransition{"leave", time.Second},
+		AppendWithTransition{HTMLFromString("<p>"), "", 0},
+		InsertAfterWithTransition{HTMLFromString("<p>"), "enter", 200 * time.Millisecond},
+		PushState{"/a?b=c", "Title"},
+		ReplaceState{"/a", ""},
+		Redirect{"https://example.com/"},
+		Reload{},
+		DefineTemplate{"row", HTMLFromString("<li>{{name}}</li>")},
+		UseTemplate{"row", map[string]string{"name": "x=y"}},
+		ReplaceText{"a\tb", "", true},
+		AddTokens{"rel", "noopener"},
+		RmTokens{"aria-describedby", ""},
+		MergeHead{HTMLFromString("<head></head>")},
+		List{[]Delta{Remove{}, SetAttr{map[string]string{}}}},
+	}}
+
+	text, err := FormatText(deltas)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ParseText(text)
+	if err != nil {
+		t.Fatal(err, "\n", text)
+	}
+
+	expected, _ := deltas.MarshalJSON()
+	if resultJSON, _ := result.MarshalJSON(); string(resultJSON) != string(expected) {
+		t.Error("Expected ", string(expected), ", got", string(resultJSON), "\n", text)
+	}
+}
+
+func TestParseTextErrors(t *testing.T) {
+	errors := map[string]string{
+		"body {":                     "wit: line 1: expected }",
+		"}":                          "wit: line 1: unexpected }",
+		"\nhtml":                     "wit: line 2: html requires html",
+		"body > p":                   "wit: line 1: expected { after selector",
+		"scroll-to x":                "wit: line 1: top requires an integer",
+		"delay { remove }":           "wit: line 1: delay requires duration",
+		"parent > p { remove }":      `wit: line 1: unexpected ">"`,
+		"attr foo":                   "wit: line 1: expected = after foo",
+		"remove now":                 `wit: line 1: unexpected "now"`,
+		"html \"<p>":                 "wit: line 1: unterminated string",
+		"dispatch-event x {":         `wit: line 1: unexpected '{'`,
+		"dispatch-event x \"{nope\"": "wit: line 1: detail requires JSON: invalid character 'n' looking for beginning of object key string",
+	}
+
+	for src, expected := range errors {
+		if _, err := ParseText(src); err == nil || err.Error() != expected {
+			t.Error("Expected ", expected, " for ", src, ", got", err)
+		}
+	}
+}
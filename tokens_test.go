@@ -0,0 +1,25 @@
+package wit
+
+import "testing"
+
+func TestTokens(t *testing.T) {
+	doc := NewDocument()
+	tokens := First{Body, List{[]Delta{
+		HTML{HTMLFromString(`<input aria-describedby="  hint ">`)},
+		First{S("input"), List{[]Delta{
+			AddTokens{"aria-describedby", "hint"},
+			RmTokens{"aria-describedby", "hint"},
+			AddTokens{"aria-describedby", "error"},
+			RmTokens{"aria-labelledby", "label"},
+		}}},
+	}}}
+
+	tokens.Apply(doc)
+
+	expected := `<!DOCTYPE html><html><head></head><body><input aria-describedby="error"/></body></html>`
+	if result := render(doc); result != expected {
+		t.Error("Expected ", expected, ", got", result)
+	}
+
+	assertJSONRoundTrip(t, List{[]Delta{tokens}})
+}
@@ -0,0 +1,27 @@
+package wit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTransitions(t *testing.T) {
+	doc := NewDocument()
+	transitions := First{Body, List{[]Delta{
+		AppendWithTransition{HTMLFromString(`<p id="one"></p>`), "enter", 200 * time.Millisecond},
+		First{S("#one"), InsertAfterWithTransition{HTMLFromString(`<p id="two"></p>`), "enter", 200 * time.Millisecond}},
+		First{S("#one"), RemoveWithTransition{"leave", time.Second}},
+	}}}
+
+	transitions.Apply(doc)
+
+	expected := `<!DOCTYPE html><html><head></head><body><p id="two"></p></body></html>`
+	if result := render(doc); result != expected {
+		t.Error("Expected ", expected, ", got", result)
+	}
+
+	expected = `[1,[3,"body",[36,"<p id=\"one\"></p>","enter",200],[3,"#one",[37,"<p id=\"two\"></p>","enter",200]],[3,"#one",[35,"leave",1000]]]]`
+	if result := assertJSONRoundTrip(t, List{[]Delta{transitions}}); result != expected {
+		t.Error("Expected ", expected, ", got", result)
+	}
+}
@@ -0,0 +1,511 @@
+package wit
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// DecodeError describes a malformed delta found while decoding strictly
+type DecodeError struct {
+	// Path locates the offending value, e.g. $[2][3]
+	Path string
+	Msg  string
+
+	// hard errors, i.e. protocol version mismatches, are reported even when
+	// not decoding strictly
+	hard bool
+}
+
+func (e *DecodeError) Error() string {
+	return e.Path + ": " + e.Msg
+}
+
+// UnmarshalStrict decodes the provided JSON payload into a delta, returning
+// a *DecodeError for unknown labels and malformed arguments instead of
+// silently dropping them
+func UnmarshalStrict(payload []byte) (Delta, error) {
+	input, err := unmarshalJSONInput(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeJSONDelta(input, "$", decodeOptions{true, ProtocolVersion})
+}
+
+// unmarshalJSONInput parses a JSON-encoded delta, keeping arguments which
+// hold arbitrary JSON, such as event details, as raw messages so their
+// numbers and key order survive unchanged
+func unmarshalJSONInput(payload []byte) ([]interface{}, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil, err
+	}
+
+	input := make([]interface{}, len(raw))
+	var spec labelSpec
+
+	for i, item := range raw {
+		var arg labelArg
+		if i > 0 {
+			arg, _ = spec.arg(i)
+		}
+
+		switch {
+		case arg.kind == jsonArg:
+			input[i] = item
+			continue
+
+		case arg.kind == deltaArg && len(item) > 0 && item[0] == '[':
+			child, err := unmarshalJSONInput(item)
+			if err != nil {
+				return nil, err
+			}
+
+			input[i] = child
+			continue
+		}
+
+		if err := json.Unmarshal(item, &input[i]); err != nil {
+			return nil, err
+		}
+
+		if i == 0 {
+			if label, ok := labelOf(input[0]); ok {
+				spec = labels[label]
+			}
+		}
+	}
+
+	return input, nil
+}
+
+func unmarshalJSONDelta(input []interface{}) Delta {
+	delta, _ := decodeJSONDelta(input, "$", decodeOptions{false, ProtocolVersion})
+	return delta
+}
+
+type decodeOptions struct {
+	strict  bool
+	version int
+}
+
+// jsonArgs reads the arguments of a JSON-encoded delta. Missing or invalid
+// required arguments always make the delta fail, optional ones only do so
+// in strict mode and default to their zero value otherwise. Hard errors,
+// i.e. protocol version mismatches, are reported even when not strict.
+type jsonArgs struct {
+	input []interface{}
+	path  string
+	name  string
+	decodeOptions
+	err  error
+	hard bool
+}
+
+func (a *jsonArgs) at(i int) string {
+	return a.path + "[" + strconv.Itoa(i) + "]"
+}
+
+func (a *jsonArgs) fail(path, msg string) {
+	if a.err == nil {
+		a.err = &DecodeError{Path: path, Msg: msg}
+	}
+}
+
+func (a *jsonArgs) invalid(path, msg string) {
+	if a.strict {
+		a.fail(path, msg)
+	}
+}
+
+func (a *jsonArgs) str(i int) string {
+	if i < len(a.input) {
+		if str, ok := a.input[i].(string); ok {
+			return str
+		}
+	}
+
+	a.fail(a.at(i), a.name+" label requires string argument")
+	return ""
+}
+
+func (a *jsonArgs) optStr(i int) string {
+	if i >= len(a.input) {
+		return ""
+	}
+
+	str, ok := a.input[i].(string)
+	if !ok {
+		a.invalid(a.at(i), a.name+" label requires string argument")
+	}
+
+	return str
+}
+
+func (a *jsonArgs) optInt(i int) int {
+	if i >= len(a.input) {
+		return 0
+	}
+
+	number, ok := a.input[i].(float64)
+	if !ok {
+		a.invalid(a.at(i), a.name+" label requires numeric argument")
+	}
+
+	return int(number)
+}
+
+func (a *jsonArgs) millis(i int) time.Duration {
+	return time.Duration(a.optInt(i)) * time.Millisecond
+}
+
+func (a *jsonArgs) optBool(i int) bool {
+	if i >= len(a.input) {
+		return false
+	}
+
+	value, ok := a.input[i].(bool)
+	if !ok {
+		a.invalid(a.at(i), a.name+" label requires boolean argument")
+	}
+
+	return value
+}
+
+func (a *jsonArgs) optStrMap(i int) map[string]string {
+	strMap := map[string]string{}
+	if i >= len(a.input) {
+		return strMap
+	}
+
+	jsonMap, ok := a.input[i].(map[string]interface{})
+	if !ok {
+		a.invalid(a.at(i), a.name+" label requires object argument")
+		return strMap
+	}
+
+	for key, value := range jsonMap {
+		if str, ok := value.(string); ok {
+			strMap[key] = str
+		} else {
+			a.invalid(a.at(i)+"["+strconv.Quote(key)+"]", a.name+" label requires string values")
+		}
+	}
+
+	return strMap
+}
+
+func (a *jsonArgs) strMap(i int) map[string]string {
+	if i >= len(a.input) {
+		a.fail(a.at(i), a.name+" label requires object argument")
+	}
+
+	return a.optStrMap(i)
+}
+
+func (a *jsonArgs) strSlice(offset int) []string {
+	strSlice := []string{}
+	for i := offset; i < len(a.input); i++ {
+		if str, ok := a.input[i].(string); ok {
+			strSlice = append(strSlice, str)
+		} else {
+			a.invalid(a.at(i), a.name+" label requires string arguments")
+		}
+	}
+
+	return strSlice
+}
+
+func (a *jsonArgs) rawJSON(i int) json.RawMessage {
+	if i >= len(a.input) || a.input[i] == nil {
+		return nil
+	}
+
+	if raw, ok := a.input[i].(json.RawMessage); ok {
+		if string(raw) == "null" {
+			return nil
+		}
+
+		return raw
+	}
+
+	raw, err := json.Marshal(a.input[i])
+	if err != nil {
+		a.invalid(a.at(i), err.Error())
+		return nil
+	}
+
+	return raw
+}
+
+func (a *jsonArgs) keyedChildren(offset int) []KeyedChild {
+	if (len(a.input)-offset)%2 != 0 {
+		a.invalid(a.at(len(a.input)-1), a.name+" label requires key and HTML pairs")
+	}
+
+	children := []KeyedChild{}
+	for i := offset; i+1 < len(a.input); i += 2 {
+		key, ok := a.input[i].(string)
+		if !ok {
+			a.invalid(a.at(i), a.name+" label requires string keys")
+			continue
+		}
+
+		switch html := a.input[i+1].(type) {
+		case string:
+			children = append(children, KeyedChild{key, HTMLFromString(html)})
+		case nil:
+			children = append(children, KeyedChild{key, nil})
+		default:
+			a.invalid(a.at(i+1), a.name+" label requires string or null HTML")
+		}
+	}
+
+	return children
+}
+
+func (a *jsonArgs) deltas(offset int) []Delta {
+	deltas := make([]Delta, 0, len(a.input))
+
+	for i := offset; i < len(a.input); i++ {
+		subjson, ok := a.input[i].([]interface{})
+		if !ok {
+			a.invalid(a.at(i), a.name+" label requires delta arguments")
+			continue
+		}
+
+		delta, err := decodeJSONDelta(subjson, a.at(i), a.decodeOptions)
+		if err != nil {
+			// Keep the first error, unless a hard one follows a soft one
+			decodeErr, ok := err.(*DecodeError)
+			hard := ok && decodeErr.hard
+			if a.err == nil || hard && !a.hard {
+				a.err = err
+				a.hard = hard
+			}
+
+			continue
+		}
+
+		if delta != nil {
+			deltas = append(deltas, delta)
+		}
+	}
+
+	return deltas
+}
+
+func (a *jsonArgs) list(offset int) List {
+	return List{a.deltas(offset)}
+}
+
+func (a *jsonArgs) delta(offset int) Delta {
+	list := a.list(offset)
+
+	if len(list.Deltas) == 1 {
+		return list.Deltas[0]
+	}
+
+	return list
+}
+
+func decodeJSONDelta(input []interface{}, path string, opts decodeOptions) (Delta, error) {
+	if len(input) == 0 {
+		if opts.strict {
+			return nil, &DecodeError{Path: path, Msg: "empty delta"}
+		}
+
+		return nil, nil
+	}
+
+	number, ok := input[0].(float64)
+	if !ok || number != float64(int(number)) {
+		if opts.strict {
+			return nil, &DecodeError{Path: path + "[0]", Msg: "label must be an integer"}
+		}
+
+		return nil, nil
+	}
+
+	label := Op(number)
+	spec := labels[label]
+	a := &jsonArgs{input, path, spec.name, opts, nil, false}
+	var delta Delta
+
+	if spec.version > opts.version {
+		a.err = versionError(path+"[0]", spec)
+		a.hard = true
+	}
+
+	switch label {
+	case OpList:
+		delta = a.list(1)
+
+	case OpRoot:
+		delta = Root{a.delta(1)}
+
+	case OpFirst:
+		delta = First{S(a.str(1)), a.delta(2)}
+
+	case OpAll:
+		delta = All{S(a.str(1)), a.delta(2)}
+
+	case OpRange:
+		delta = Range{a.str(1), a.delta(2)}
+
+	case OpParent:
+		delta = Parent{a.delta(1)}
+
+	case OpFirstChild:
+		delta = FirstChild{a.delta(1)}
+
+	case OpLastChild:
+		delta = LastChild{a.delta(1)}
+
+	case OpPrevSibling:
+		delta = PrevSibling{a.delta(1)}
+
+	case OpNextSibling:
+		delta = NextSibling{a.delta(1)}
+
+	case OpRemove:
+		delta = Remove{}
+
+	case OpClear:
+		delta = Clear{}
+
+	case OpHTML:
+		delta = HTML{HTMLFromString(a.str(1))}
+
+	case OpReplace:
+		delta = Replace{HTMLFromString(a.str(1))}
+
+	case OpAppend:
+		delta = Append{HTMLFromString(a.str(1))}
+
+	case OpPrepend:
+		delta = Prepend{HTMLFromString(a.str(1))}
+
+	case OpInsertAfter:
+		delta = InsertAfter{HTMLFromString(a.str(1))}
+
+	case OpInsertBefore:
+		delta = InsertBefore{HTMLFromString(a.str(1))}
+
+	case OpSetAttr:
+		delta = SetAttr{a.strMap(1)}
+
+	case OpReplaceAttr:
+		delta = ReplaceAttr{a.strMap(1)}
+
+	case OpRmAttr:
+		delta = RmAttr{a.strSlice(1)}
+
+	case OpSetStyles:
+		delta = SetStyles{a.strMap(1)}
+
+	case OpRmStyles:
+		delta = RmStyles{a.strSlice(1)}
+
+	case OpAddClasses:
+		delta = AddClasses{a.str(1)}
+
+	case OpRmClasses:
+		delta = RmClasses{a.str(1)}
+
+	case OpAddTokens:
+		delta = AddTokens{a.str(1), a.optStr(2)}
+
+	case OpRmTokens:
+		delta = RmTokens{a.str(1), a.optStr(2)}
+
+	case OpLoadScript:
+		delta = LoadScript{a.str(1), a.optStrMap(2)}
+
+	case OpLoadStylesheet:
+		delta = LoadStylesheet{a.str(1), a.optStrMap(2)}
+
+	case OpReconcileChildren:
+		delta = ReconcileChildren{a.keyedChildren(1)}
+
+	case OpFocus:
+		delta = Focus{}
+
+	case OpBlur:
+		delta = Blur{}
+
+	case OpScrollIntoView:
+		delta = ScrollIntoView{a.optStrMap(1)}
+
+	case OpScrollTo:
+		delta = ScrollTo{a.optInt(1), a.optInt(2)}
+
+	case OpSelectText:
+		delta = SelectText{a.optInt(1), a.optInt(2)}
+
+	case OpDispatchEvent:
+		delta = DispatchEvent{a.str(1), a.rawJSON(2), a.optBool(3)}
+
+	case OpDelay:
+		delta = Delay{a.millis(1), a.delta(2)}
+
+	case OpRemoveWithTransition:
+		delta = RemoveWithTransition{a.str(1), a.millis(2)}
+
+	case OpAppendWithTransition:
+		delta = AppendWithTransition{HTMLFromString(a.str(1)), a.str(2), a.millis(3)}
+
+	case OpInsertAfterWithTransition:
+		delta = InsertAfterWithTransition{HTMLFromString(a.str(1)), a.str(2), a.millis(3)}
+
+	case OpPushState:
+		delta = PushState{a.str(1), a.optStr(2)}
+
+	case OpReplaceState:
+		delta = ReplaceState{a.str(1), a.optStr(2)}
+
+	case OpRedirect:
+		delta = Redirect{a.str(1)}
+
+	case OpReload:
+		delta = Reload{}
+
+	case OpDefineTemplate:
+		delta = DefineTemplate{a.str(1), HTMLFromString(a.str(2))}
+
+	case OpUseTemplate:
+		delta = UseTemplate{a.str(1), a.optStrMap(2)}
+
+	case OpReplaceText:
+		delta = ReplaceText{a.str(1), a.optStr(2), a.optBool(3)}
+
+	case OpMergeHead:
+		delta = MergeHead{HTMLFromString(a.str(1))}
+
+	default:
+		decoder := registeredDecoder(label)
+		if decoder == nil {
+			a.fail(path+"[0]", "unknown label "+strconv.Itoa(int(label)))
+			break
+		}
+
+		custom, err := decoder(input)
+		if err != nil {
+			a.fail(path, err.Error())
+			break
+		}
+
+		delta = custom
+	}
+
+	if a.err != nil {
+		if opts.strict || a.hard {
+			return nil, a.err
+		}
+
+		return nil, nil
+	}
+
+	return delta, nil
+}
@@ -0,0 +1,169 @@
+package wit
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestUnmarshalStrict(t *testing.T) {
+	result, err := UnmarshalStrict([]byte(expectedJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resultJSON, _ := result.MarshalJSON()
+	if string(resultJSON) != expectedJSON {
+		t.Error("Expected ", expectedJSON, ", got", string(resultJSON))
+	}
+
+	errors := map[string]string{
+		`[]`:                         `$: empty delta`,
+		`["1"]`:                      `$[0]: label must be an integer`,
+		`[999]`:                      `$[0]: unknown label 999`,
+		`[3]`:                        `$[1]: selector label requires string argument`,
+		`[1,[10],[3,"a",[10],[12]]]`: `$[2][3][1]: html label requires string argument`,
+		`[1,[3],[12]]`:               `$[1][1]: selector label requires string argument`,
+		`[1,[10],"foo"]`:             `$[2]: list label requires delta arguments`,
+		`[18,{"a":1}]`:               `$[1]["a"]: set attr label requires string values`,
+		`[20,"a",2]`:                 `$[2]: rm attr label requires string arguments`,
+		`[27,"a"]`:                   `$[1]: reconcile children label requires key and HTML pairs`,
+		`[31,"10"]`:                  `$[1]: scroll to label requires numeric argument`,
+		`[65537]`:                    `$: missing series`,
+	}
+
+	for payload, expected := range errors {
+		_, err := UnmarshalStrict([]byte(payload))
+		if err == nil || err.Error() != expected {
+			t.Error("Expected ", expected, " for ", payload, ", got", err)
+		}
+	}
+}
+
+func TestUnmarshalLenient(t *testing.T) {
+	payloads := []string{`[]`, `[3]`, `[4]`, `[12]`, `[18]`, `[19]`, `[21]`, `[27,1,2,"a"]`, `[33]`, `[36,"a"]`, `[1,"x",[null],[3]]`}
+
+	for _, payload := range payloads {
+		var result List
+		if err := (&result).UnmarshalJSON([]byte(payload)); err != nil {
+			t.Error(err)
+		}
+
+		for _, delta := range result.Deltas {
+			if delta == nil {
+				t.Error("Unexpected nil delta for", payload)
+			}
+		}
+	}
+
+	var result List
+	(&result).UnmarshalJSON([]byte(`[1,[10],[3],[18,{"a":"b","c":1}]]`))
+	resultJSON, _ := result.MarshalJSON()
+
+	expected := `[1,[10],[18,{"a":"b"}]]`
+	if string(resultJSON) != expected {
+		t.Error("Expected ", expected, ", got", string(resultJSON))
+	}
+}
+
+func TestStrictDecoder(t *testing.T) {
+	decoder := NewDecoder(strings.NewReader(`[10] [3] [11]`))
+	decoder.Strict()
+
+	if _, err := decoder.Decode(); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := decoder.Decode(); err == nil {
+		t.Error("Expected error for malformed delta")
+	}
+
+	if delta, err := decoder.Decode(); err != nil || delta != (Clear{}) {
+		t.Error("Expected decoding to continue after an error, got", delta, err)
+	}
+
+	decoder = NewDecoder(strings.NewReader(`[1,[3],[12],[28]]`))
+	decoder.Strict()
+	decoder.Version(1)
+
+	if _, err := decoder.Decode(); err == nil || err.Error() != "$[3][0]: focus label requires protocol version 2" {
+		t.Error("Expected version error to take precedence, got", err)
+	}
+}
+
+// sampleArgs holds a valid value for every argument kind, which must survive
+// a decoding and encoding round trip unchanged
+var sampleArgs = map[argKind]interface{}{
+	stringArg:       "a",
+	selectorArg:     "p",
+	htmlArg:         "<b>a</b>",
+	nullableHTMLArg: "<i>b</i>",
+	intArg:          float64(7),
+	millisArg:       float64(1500),
+	boolArg:         true,
+	strMapArg:       map[string]interface{}{"a": "b"},
+	jsonArg:         map[string]interface{}{"a": []interface{}{float64(1), "b"}},
+	deltaArg:        []interface{}{float64(OpClear)},
+}
+
+// TestLabelShapes makes sure the argument shapes described by the labels
+// table match the ones expected by decodeJSONDelta
+func TestLabelShapes(t *testing.T) {
+	for label, spec := range labels {
+		args := append(append([]labelArg{}, spec.args...), spec.rest...)
+		input := []interface{}{float64(label)}
+		required := 1
+
+		for i, arg := range args {
+			input = append(input, sampleArgs[arg.kind])
+			if !arg.optional && i < len(spec.args) {
+				required = len(input)
+			}
+		}
+
+		var b bytes.Buffer
+		encoder := json.NewEncoder(&b)
+		encoder.SetEscapeHTML(false)
+		encoder.Encode(input)
+		expected := strings.TrimSpace(b.String())
+
+		delta, err := decodeJSONDelta(input, "$", decodeOptions{true, ProtocolVersion})
+		if err != nil {
+			t.Error("Expected", expected, "to decode, got", err)
+			continue
+		}
+
+		if deltaJSON, _ := delta.MarshalJSON(); string(deltaJSON) != expected {
+			t.Error("Expected ", expected, ", got", string(deltaJSON))
+		}
+
+		if _, err := decodeJSONDelta(input[:required], "$", decodeOptions{true, ProtocolVersion}); err != nil {
+			t.Error("Expected optional arguments of", spec.name, "to be optional, got", err)
+		}
+
+		for i := 1; i < required; i++ {
+			if _, err := decodeJSONDelta(input[:i], "$", decodeOptions{true, ProtocolVersion}); err == nil {
+				t.Error("Expected", spec.name, "to require", args[i-1].name)
+			}
+		}
+
+		for i, arg := range args {
+			if arg.kind == jsonArg {
+				continue
+			}
+
+			invalid := append([]interface{}{}, input...)
+			invalid[i+1] = []interface{}{"x"}
+			if arg.kind == deltaArg {
+				invalid[i+1] = "x"
+			}
+
+			_, err := decodeJSONDelta(invalid, "$", decodeOptions{true, ProtocolVersion})
+			if decodeErr, ok := err.(*DecodeError); !ok || !strings.HasPrefix(decodeErr.Path, "$["+strconv.Itoa(i+1)+"]") {
+				t.Error("Expected invalid", spec.name, arg.name, "to fail, got", err)
+			}
+		}
+	}
+}
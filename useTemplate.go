@@ -0,0 +1,85 @@
+package wit
+
+import (
+	"regexp"
+	"strconv"
+
+	"golang.org/x/net/html"
+)
+
+var placeholder = regexp.MustCompile(`\{\{\s*([^{}\s]+)\s*\}\}`)
+
+// UseTemplate appends an instance of a previously defined template to
+// matching elements, filling its placeholders with the provided params.
+// Missing params are replaced by the empty string. Placeholders inside
+// scripts, styles and other raw text elements are left untouched, as params
+// could not be escaped there.
+type UseTemplate struct {
+	Name   string
+	Params map[string]string
+}
+
+// Apply applies the delta to the provided elements
+func (u UseTemplate) Apply(d Document) {
+	template, ok := d.templates[u.Name]
+	if !ok {
+		return
+	}
+
+	for _, node := range d.nodes {
+		if node.Type != html.ElementNode {
+			continue
+		}
+
+		children := template.Nodes(node)
+		for _, child := range children {
+			u.fill(child)
+			node.AppendChild(child)
+		}
+	}
+}
+
+func (u UseTemplate) replace(str string) string {
+	return placeholder.ReplaceAllStringFunc(str, func(match string) string {
+		return u.Params[placeholder.FindStringSubmatch(match)[1]]
+	})
+}
+
+func (u UseTemplate) fill(node *html.Node) {
+	switch node.Type {
+	case html.TextNode:
+		node.Data = u.replace(node.Data)
+	case html.ElementNode:
+		for i, att := range node.Attr {
+			node.Attr[i].Val = u.replace(att.Val)
+		}
+
+		if isRawText(node) {
+			return
+		}
+	}
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		u.fill(child)
+	}
+}
+
+// MarshalJSON marshals the delta to JSON format
+func (u UseTemplate) MarshalJSON() ([]byte, error) {
+	return []byte("[" + useTemplateLabelJSON + "," + strconv.Quote(u.Name) + "," + strMapToJSON(u.Params) + "]"), nil
+}
+
+// MarshalBinary marshals the delta to the binary format
+func (u UseTemplate) MarshalBinary() ([]byte, error) {
+	return marshalBinary(u)
+}
+
+// UnmarshalBinary sets *u to the delta encoded in the binary format
+func (u *UseTemplate) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, u)
+}
+
+// Op returns the operation performed by the delta
+func (u UseTemplate) Op() Op {
+	return OpUseTemplate
+}
@@ -1,9 +1,14 @@
 package wit
 
 import (
+	"bytes"
+	"encoding/json"
+	"sort"
 	"strconv"
+	"strings"
 
 	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
 )
 
 func cloneNode(node *html.Node, cache map[*html.Node]*html.Node) *html.Node {
@@ -35,8 +40,37 @@ func cloneNode(node *html.Node, cache map[*html.Node]*html.Node) *html.Node {
 	return newNode
 }
 
-func parseStyle(style string) map[string]string {
-	styleMap := map[string]string{}
+// declarations holds CSS declarations, keeping the order in which
+// properties were first set
+type declarations struct {
+	keys   []string
+	values map[string]string
+}
+
+func (d *declarations) set(key, value string) {
+	if _, ok := d.values[key]; !ok {
+		d.keys = append(d.keys, key)
+	}
+
+	d.values[key] = value
+}
+
+func (d *declarations) remove(key string) {
+	if _, ok := d.values[key]; !ok {
+		return
+	}
+
+	delete(d.values, key)
+	for i, k := range d.keys {
+		if k == key {
+			d.keys = append(d.keys[:i], d.keys[i+1:]...)
+			return
+		}
+	}
+}
+
+func parseStyle(style string) *declarations {
+	styleMap := &declarations{values: map[string]string{}}
 
 	key := ""
 	value := ""
@@ -90,7 +124,7 @@ func parseStyle(style string) map[string]string {
 			switch r {
 			case ';':
 				if key != "" {
-					styleMap[key] = value
+					styleMap.set(key, value)
 				}
 
 				key = ""
@@ -130,115 +164,152 @@ func parseStyle(style string) map[string]string {
 	}
 
 	if fillingValue && key != "" {
-		styleMap[key] = value
+		styleMap.set(key, value)
 	}
 
 	return styleMap
 }
 
-func buildStyle(style map[string]string) string {
+func buildStyle(style *declarations) string {
 	attr := ""
-	for key, value := range style {
-		attr += key + ": " + value + ";"
+	for _, key := range style.keys {
+		attr += key + ": " + style.values[key] + ";"
 	}
 
 	return attr
 }
 
-func parseClass(class string) map[string]bool {
-	currentClass := ""
-	classes := map[string]bool{}
+// parseTokens returns the unique tokens of the list in order
+func parseTokens(list string) []string {
+	currentToken := ""
+	tokens := []string{}
+	seen := map[string]bool{}
 
 	flush := func() {
-		if currentClass != "" {
-			classes[currentClass] = true
-			currentClass = ""
+		if currentToken != "" {
+			if !seen[currentToken] {
+				seen[currentToken] = true
+				tokens = append(tokens, currentToken)
+			}
+
+			currentToken = ""
 		}
 	}
 
-	for _, r := range class {
+	for _, r := range list {
 		switch r {
 		case ' ', '\t', '\r', '\n', '\f':
 			flush()
 		default:
-			currentClass += string(r)
+			currentToken += string(r)
 		}
 	}
 
 	flush()
-	return classes
+	return tokens
 }
 
-func buildClass(classes map[string]bool) string {
-	class := ""
-	i := 0
-
-	for key, value := range classes {
-		if i != 0 {
-			class += " "
-		}
-
-		if value {
-			class += key
-		}
+func buildTokens(tokens []string) string {
+	return strings.Join(tokens, " ")
+}
 
-		i++
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
 	}
 
-	return class
+	sort.Strings(keys)
+	return keys
 }
 
 func strMapToJSON(args map[string]string) string {
-	result := "{"
-	i := 0
+	var result strings.Builder
+	result.WriteByte('{')
 
-	for key, value := range args {
+	for i, key := range sortedKeys(args) {
 		if i != 0 {
-			result += ","
+			result.WriteByte(',')
 		}
 
-		result += strconv.Quote(key) + ":" + strconv.Quote(value)
-		i++
+		result.WriteString(strconv.Quote(key))
+		result.WriteByte(':')
+		result.WriteString(strconv.Quote(args[key]))
 	}
 
-	result += "}"
-	return result
+	result.WriteByte('}')
+	return result.String()
 }
 
-func deltasToCSV(deltas []Delta) string {
-	result := ""
+func strSliceToQuotedCSV(arr []string) string {
+	var result strings.Builder
 
-	for _, delta := range deltas {
-		deltaJSON, err := delta.MarshalJSON()
-		if err != nil {
-			return ""
-		}
+	for _, str := range arr {
+		result.WriteByte(',')
+		result.WriteString(strconv.Quote(str))
+	}
+
+	return result.String()
+}
 
-		result += "," + string(deltaJSON)
+func quoteCSSString(str string) string {
+	result := "\""
+
+	for _, r := range str {
+		switch r {
+		case '"', '\\':
+			result += "\\" + string(r)
+		case '\n':
+			result += "\\a "
+		default:
+			result += string(r)
+		}
 	}
 
-	return result
+	return result + "\""
 }
 
-func deltaToCSV(delta Delta) string {
-	if list, ok := delta.(List); ok {
-		return deltasToCSV(list.Deltas)
+func getAttr(node *html.Node, key string) (string, bool) {
+	for _, att := range node.Attr {
+		if att.Namespace == "" && att.Key == key {
+			return att.Val, true
+		}
 	}
 
-	deltaJSON, err := delta.MarshalJSON()
-	if err != nil {
-		return ""
+	return "", false
+}
+
+func setAttr(node *html.Node, key, value string) {
+	for i, att := range node.Attr {
+		if att.Namespace == "" && att.Key == key {
+			node.Attr[i].Val = value
+			return
+		}
 	}
 
-	return "," + string(deltaJSON)
+	node.Attr = append(node.Attr, html.Attribute{Key: key, Val: value})
 }
 
-func strSliceToQuotedCSV(arr []string) string {
-	result := ""
+// isRawText reports whether the contents of the node are rendered without
+// escaping, as is the case for scripts and styles
+func isRawText(node *html.Node) bool {
+	if node.Type != html.ElementNode {
+		return false
+	}
 
-	for _, str := range arr {
-		result += "," + strconv.Quote(str)
+	switch node.DataAtom {
+	case atom.Script, atom.Style, atom.Iframe, atom.Noembed, atom.Noframes, atom.Noscript, atom.Plaintext, atom.Xmp:
+		return true
+	}
+
+	return false
+}
+
+func compactJSON(raw []byte) (string, error) {
+	var b bytes.Buffer
+	if err := json.Compact(&b, raw); err != nil {
+		return "", err
 	}
 
-	return result
+	return b.String(), nil
 }
@@ -0,0 +1,80 @@
+package wit
+
+// Visitor visits the deltas found by Walk. If Visit returns a non-nil
+// visitor w, Walk visits the children of the delta with w, followed by a
+// call of w.Visit(nil).
+type Visitor interface {
+	Visit(delta Delta) (w Visitor)
+}
+
+// Walk traverses a delta tree in depth-first order. It starts by calling
+// v.Visit(delta), and descends through lists, traversals, delays and ranges.
+// Nil deltas are not visited.
+func Walk(delta Delta, v Visitor) {
+	if delta == nil {
+		return
+	}
+
+	if v = v.Visit(delta); v == nil {
+		return
+	}
+
+	for _, child := range Children(delta) {
+		Walk(child, v)
+	}
+
+	v.Visit(nil)
+}
+
+type inspector func(Delta) bool
+
+func (f inspector) Visit(delta Delta) Visitor {
+	if f(delta) {
+		return f
+	}
+
+	return nil
+}
+
+// Inspect traverses a delta tree in depth-first order, calling f for every
+// delta. If f returns true, Inspect visits the children of the delta,
+// followed by a call of f(nil).
+func Inspect(delta Delta, f func(Delta) bool) {
+	Walk(delta, inspector(f))
+}
+
+// Children returns the deltas directly contained in the provided one
+func Children(delta Delta) []Delta {
+	var child Delta
+
+	switch d := delta.(type) {
+	case List:
+		return d.Deltas
+	case Root:
+		child = d.Delta
+	case First:
+		child = d.Delta
+	case All:
+		child = d.Delta
+	case Parent:
+		child = d.Delta
+	case FirstChild:
+		child = d.Delta
+	case LastChild:
+		child = d.Delta
+	case PrevSibling:
+		child = d.Delta
+	case NextSibling:
+		child = d.Delta
+	case Delay:
+		child = d.Delta
+	case Range:
+		child = d.Delta
+	}
+
+	if child == nil {
+		return nil
+	}
+
+	return []Delta{child}
+}
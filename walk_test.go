@@ -0,0 +1,77 @@
+package wit
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOp(t *testing.T) {
+	for _, test := range []struct {
+		delta Delta
+		op    Op
+	}{
+		{List{}, OpList},
+		{First{S("body"), nil}, OpFirst},
+		{HTML{}, OpHTML},
+		{Delay{time.Second, nil}, OpDelay},
+		{AddTokens{"rel", "next"}, OpAddTokens},
+		{chartUpdate{"sales"}, chartUpdateLabel},
+	} {
+		if op := OpOf(test.delta); op != test.op {
+			t.Error("Expected ", test.op, ", got", op)
+		}
+	}
+
+	if name := OpInsertAfterWithTransition.String(); name != "InsertAfterWithTransition" {
+		t.Error("Expected InsertAfterWithTransition, got", name)
+	}
+
+	if name := Op(chartUpdateLabel).String(); name != "Op(65537)" {
+		t.Error("Expected Op(65537), got", name)
+	}
+
+	if err := checkVersion(List{[]Delta{First{S("#chart"), chartUpdate{"sales"}}}}, 1); err != nil {
+		t.Error("Expected custom deltas to pass the version check, got", err)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	tree := List{[]Delta{
+		First{S("body"), List{[]Delta{
+			HTML{HTMLFromString("<ul></ul>")},
+			Delay{time.Second, Parent{Remove{}}},
+		}}},
+		All{S("li"), AddClasses{"item"}},
+		Range{"items", nil},
+	}}
+
+	var ops []string
+	Inspect(tree, func(delta Delta) bool {
+		if delta == nil {
+			ops = append(ops, ")")
+			return false
+		}
+
+		ops = append(ops, OpOf(delta).String())
+		return true
+	})
+
+	expected := "List First List HTML ) Delay Parent Remove ) ) ) ) ) All AddClasses ) ) Range ) )"
+	if result := strings.Join(ops, " "); result != expected {
+		t.Error("Expected ", expected, ", got", result)
+	}
+
+	count := 0
+	Inspect(tree, func(delta Delta) bool {
+		if delta != nil {
+			count++
+		}
+
+		return delta == nil || OpOf(delta) != OpFirst
+	})
+
+	if count != 5 {
+		t.Error("Expected 5 visited deltas, got", count)
+	}
+}